@@ -209,3 +209,47 @@ func BenchmarkCompareFormatTrace(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkFormatSymbolCache(b *testing.B) {
+	defer bruh.SetSymbolCacheSize(bruh.DefaultSymbolCacheSize)
+	for _, tc := range cases {
+		for _, size := range []int{0, bruh.DefaultSymbolCacheSize} {
+			b.Run(fmt.Sprintf("cache=%v/layers=%v", size > 0, tc.layers), func(b *testing.B) {
+				bruh.SetSymbolCacheSize(size)
+				err := wrapBruh(tc.layers)
+				b.ResetTimer()
+				var str string
+				for n := 0; n < b.N; n++ {
+					str = bruh.StringFormat(err, bruh.BruhStackedFormatter)
+				}
+				b.StopTimer()
+				global = str
+			})
+		}
+	}
+}
+
+func BenchmarkStackCaptureBackend(b *testing.B) {
+	defer bruh.ResetStackCapturePolicy()
+	backends := []struct {
+		name    string
+		backend bruh.StackCaptureBackend
+	}{
+		{"callers", bruh.StackCaptureCallers},
+		{"framepointers", bruh.StackCaptureFramePointers},
+	}
+	for _, tc := range cases {
+		for _, bc := range backends {
+			b.Run(fmt.Sprintf("backend=%v/layers=%v", bc.name, tc.layers), func(b *testing.B) {
+				bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Backend: bc.backend})
+				var err error
+				b.ResetTimer()
+				for n := 0; n < b.N; n++ {
+					err = wrapBruh(tc.layers)
+				}
+				b.StopTimer()
+				global = err
+			})
+		}
+	}
+}
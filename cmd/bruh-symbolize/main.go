@@ -0,0 +1,106 @@
+// Command bruh-symbolize resolves the program counters of PC-only error
+// reports created by bruh.NewPCReport and prints the errors with one of the
+// bruh formatters.
+//
+// Usage:
+//
+//	bruh-symbolize -binary <path> [-format <format>] [report.json]
+//
+// The report is read from the given file or from stdin. The binary must be the
+// unstripped binary of the process that created the report. Supported formats
+// are: bruh, bruh-stacked, go-panic, java, python, json-document, markdown,
+// html and json. The json format prints the symbolized report, while the
+// json-document format prints the errors as formatted by bruh.JSONFormatter.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+	"github.com/aisbergg/go-bruh/pkg/symbolizer"
+)
+
+var formatters = map[string]bruh.Formatter{
+	"bruh":          bruh.BruhFormatter,
+	"bruh-stacked":  bruh.BruhStackedFormatter,
+	"go-panic":      bruh.GoPanicFormatter,
+	"java":          bruh.JavaStackTraceFormatter,
+	"python":        bruh.PythonTracebackFormatter,
+	"json-document": bruh.JSONFormatter,
+	"markdown":      bruh.MarkdownFormatter,
+	"html":          bruh.HTMLFormatter,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "bruh-symbolize:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	formatNames := make([]string, 0, len(formatters)+1)
+	for name := range formatters {
+		formatNames = append(formatNames, name)
+	}
+	formatNames = append(formatNames, "json")
+	sort.Strings(formatNames)
+
+	flags := flag.NewFlagSet("bruh-symbolize", flag.ContinueOnError)
+	binaryPath := flags.String("binary", "", "path of the unstripped binary that created the report")
+	format := flags.String("format", "bruh", "output format ("+strings.Join(formatNames, ", ")+")")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *binaryPath == "" {
+		return bruh.New("missing required flag -binary")
+	}
+	formatter, ok := formatters[*format]
+	if !ok && *format != "json" {
+		return bruh.Errorf("unknown format '%s'", *format)
+	}
+
+	// read the report
+	input := stdin
+	if flags.NArg() > 0 {
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return bruh.Wrap(err, "opening report")
+		}
+		defer f.Close()
+		input = f
+	}
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return bruh.Wrap(err, "reading report")
+	}
+	report, err := bruh.UnmarshalReport(data)
+	if err != nil {
+		return err
+	}
+
+	// symbolize the report
+	elf, err := symbolizer.OpenELF(*binaryPath)
+	if err != nil {
+		return err
+	}
+	defer elf.Close()
+	if err := elf.Symbolize(report); err != nil {
+		return bruh.Wrap(err, "symbolizing report")
+	}
+
+	// print the result
+	if formatter == nil {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	_, err = fmt.Fprintln(stdout, bruh.StringFormat(report.Err(), formatter))
+	return err
+}
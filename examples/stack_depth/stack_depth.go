@@ -7,6 +7,8 @@ import (
 )
 
 func main() {
+	// limits the number of stack frames captured per error to 6
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Depth: 6})
 	// limits the number of stack frames for serialization to 10
 	bruh.MaxChainStackDepth = 10
 
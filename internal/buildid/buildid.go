@@ -0,0 +1,28 @@
+// Package buildid reads the Go build ID of binaries.
+package buildid
+
+import "debug/elf"
+
+// FromELF returns the Go build ID stored in the `.note.go.buildid` section of
+// the given ELF file. An empty string is returned if the file has no Go build
+// ID.
+func FromELF(f *elf.File) string {
+	sec := f.Section(".note.go.buildid")
+	if sec == nil {
+		return ""
+	}
+	data, err := sec.Data()
+	if err != nil || len(data) < 16 {
+		return ""
+	}
+	// the note consists of: name size, description size, type, name (padded to
+	// 4 bytes) and description
+	nameSize := f.ByteOrder.Uint32(data[0:4])
+	descSize := f.ByteOrder.Uint32(data[4:8])
+	descStart := 12 + (uint64(nameSize)+3)&^3
+	descEnd := descStart + uint64(descSize)
+	if descEnd > uint64(len(data)) {
+		return ""
+	}
+	return string(data[descStart:descEnd])
+}
@@ -0,0 +1,302 @@
+package bruh
+
+import (
+	"container/list"
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// AggregatorOptions configures the [Aggregator] created by [NewAggregator].
+type AggregatorOptions struct {
+	// OnReport is called with the reports of the forwarded errors. It is called
+	// outside of the lock of the aggregator and may therefore be called
+	// concurrently.
+	OnReport func(report *AggregatedReport)
+	// Formatter is used to format the forwarded errors. Defaults to
+	// [BruhFormatter].
+	Formatter Formatter
+	// Interval is the minimum time between two forwarded reports of the same
+	// group. Defaults to one minute.
+	Interval time.Duration
+	// MaxGroups is the maximum number of groups that are tracked. If it is
+	// exceeded, the least recently seen group is dropped. It also bounds the
+	// number of cached fingerprints. Defaults to 1000.
+	MaxGroups int
+	// Fingerprint configures the fingerprint by which the errors are grouped,
+	// see [Fingerprint].
+	Fingerprint FingerprintOptions
+	// Now returns the current time. Defaults to [time.Now].
+	Now func() time.Time
+}
+
+// AggregatedError is a group of errors with the same fingerprint that was
+// collected by an [Aggregator].
+type AggregatedError struct {
+	// Fingerprint is the fingerprint shared by the errors of the group.
+	Fingerprint string
+	// Message is the message of the first error of the group.
+	Message string
+	// Count is the number of occurrences of the error.
+	Count int64
+	// FirstSeen is the time of the first occurrence.
+	FirstSeen time.Time
+	// LastSeen is the time of the most recent occurrence.
+	LastSeen time.Time
+}
+
+// AggregatedReport is passed to [AggregatorOptions.OnReport] when an error is
+// forwarded by an [Aggregator].
+type AggregatedReport struct {
+	AggregatedError
+	// Err is the error that triggered the report.
+	Err error
+	// Formatted is Err formatted with the configured formatter.
+	Formatted string
+	// Suppressed is the number of occurrences that were not forwarded since
+	// the previous report of the group.
+	Suppressed int64
+}
+
+// aggregatedKey maps the cheap identity of an error, see
+// [appendAggregationKey], to its fingerprint.
+type aggregatedKey struct {
+	key         string
+	fingerprint string
+}
+
+// aggregatedGroup is the state of a group tracked by an [Aggregator].
+type aggregatedGroup struct {
+	AggregatedError
+	lastReported time.Time
+	suppressed   int64
+}
+
+// Aggregator groups errors by their fingerprint, see [Fingerprint], and counts
+// their occurrences. Instead of formatting every single error, only the first
+// occurrence of a group and then at most one occurrence per interval is
+// formatted and forwarded to [AggregatorOptions.OnReport]. This keeps the cost
+// of logging low when the same error occurs at a high rate, e.g. because a
+// dependency is down. The fingerprint of an error is computed only the first
+// time an error with the same types, message templates and stacks is seen. It
+// is safe for concurrent use.
+//
+// Example usage:
+//
+//	agg := bruh.NewAggregator(bruh.AggregatorOptions{
+//	    Interval: 10 * time.Second,
+//	    OnReport: func(report *bruh.AggregatedReport) {
+//	        log.Printf("%s (suppressed %d times)", report.Formatted, report.Suppressed)
+//	    },
+//	})
+//	...
+//	agg.Add(err)
+type Aggregator struct {
+	mu     sync.Mutex
+	opts   AggregatorOptions
+	groups map[string]*list.Element // of *aggregatedGroup
+	lru    *list.List
+	// keys caches the fingerprints by the cheap identity of the errors.
+	keys   map[string]*list.Element // of *aggregatedKey
+	keyLRU *list.List
+}
+
+// NewAggregator creates a new [Aggregator] with the given options.
+func NewAggregator(opts AggregatorOptions) *Aggregator {
+	if opts.Formatter == nil {
+		opts.Formatter = BruhFormatter
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.MaxGroups <= 0 {
+		opts.MaxGroups = 1000
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Aggregator{
+		opts:   opts,
+		groups: make(map[string]*list.Element),
+		lru:    list.New(),
+		keys:   make(map[string]*list.Element),
+		keyLRU: list.New(),
+	}
+}
+
+// Add records an occurrence of err and forwards it, if it is the first
+// occurrence of its group or the interval has passed since the group was last
+// forwarded. It returns true if err was forwarded. Nil errors are ignored.
+func (a *Aggregator) Add(err error) bool {
+	if err == nil {
+		return false
+	}
+	var keyBuf [512]byte
+	key, keyed := appendAggregationKey(keyBuf[:0], err)
+	now := a.opts.Now()
+
+	a.mu.Lock()
+	var fingerprint string
+	if keyed {
+		if elem, ok := a.keys[string(key)]; ok {
+			a.keyLRU.MoveToFront(elem)
+			fingerprint = elem.Value.(*aggregatedKey).fingerprint //nolint:revive
+		}
+	}
+	if fingerprint == "" {
+		// compute the fingerprint outside of the lock, as it is expensive
+		a.mu.Unlock()
+		fingerprint = Fingerprint(err, a.opts.Fingerprint)
+		a.mu.Lock()
+		if keyed {
+			a.addKeyLocked(string(key), fingerprint)
+		}
+	}
+
+	var group *aggregatedGroup
+	elem, ok := a.groups[fingerprint]
+	if ok {
+		a.lru.MoveToFront(elem)
+		group = elem.Value.(*aggregatedGroup) //nolint:revive
+	} else {
+		if a.lru.Len() >= a.opts.MaxGroups {
+			oldest := a.lru.Back()
+			a.lru.Remove(oldest)
+			delete(a.groups, oldest.Value.(*aggregatedGroup).Fingerprint) //nolint:revive
+		}
+		group = &aggregatedGroup{
+			AggregatedError: AggregatedError{
+				Fingerprint: fingerprint,
+				Message:     Message(err),
+				FirstSeen:   now,
+			},
+		}
+		a.groups[fingerprint] = a.lru.PushFront(group)
+	}
+	group.Count++
+	group.LastSeen = now
+	if ok && now.Sub(group.lastReported) < a.opts.Interval {
+		group.suppressed++
+		a.mu.Unlock()
+		return false
+	}
+	report := &AggregatedReport{
+		AggregatedError: group.AggregatedError,
+		Err:             err,
+		Suppressed:      group.suppressed,
+	}
+	group.lastReported = now
+	group.suppressed = 0
+	a.mu.Unlock()
+
+	// format outside of the lock, as it is the expensive part
+	if a.opts.OnReport != nil {
+		report.Formatted = StringFormat(err, a.opts.Formatter)
+		a.opts.OnReport(report)
+	}
+	return true
+}
+
+// addKeyLocked caches the fingerprint of the given key. If the cache is full,
+// the least recently used key is dropped. The lock must be held.
+func (a *Aggregator) addKeyLocked(key, fingerprint string) {
+	if elem, ok := a.keys[key]; ok {
+		// added concurrently
+		a.keyLRU.MoveToFront(elem)
+		return
+	}
+	if a.keyLRU.Len() >= a.opts.MaxGroups {
+		oldest := a.keyLRU.Back()
+		a.keyLRU.Remove(oldest)
+		delete(a.keys, oldest.Value.(*aggregatedKey).key) //nolint:revive
+	}
+	a.keys[key] = a.keyLRU.PushFront(&aggregatedKey{key: key, fingerprint: fingerprint})
+}
+
+// appendAggregationKey appends a cheap identity of err to dst: the type name,
+// the message template and the program counters of each error of the chain.
+// Errors with the same identity have the same fingerprint, see [Fingerprint].
+// It returns false if err has no such identity, e.g. because the chain
+// branches into an error tree or carries symbolized stacks.
+func appendAggregationKey(dst []byte, err error) ([]byte, bool) {
+	appendString := func(dst []byte, s string) []byte {
+		dst = binary.AppendUvarint(dst, uint64(len(s)))
+		return append(dst, s...)
+	}
+	var pcs [maxStackCaptureDepth]uintptr
+	for uerr := err; uerr != nil; uerr = Unwrap(uerr) {
+		switch uerr.(type) {
+		case *wrapErrors, multiUnwraper, framer:
+			return dst, false
+		}
+		dst = appendString(dst, typeName(uerr))
+
+		var parts []string
+		if fp, ok := uerr.(fingerprinter); ok {
+			parts = fp.FingerprintParts()
+		}
+		if parts != nil {
+			dst = binary.AppendUvarint(dst, uint64(len(parts)))
+			for _, part := range parts {
+				dst = appendString(dst, part)
+			}
+		} else {
+			dst = append(dst, 0)
+			switch e := uerr.(type) {
+			case *Err:
+				if e.lazyMsg != nil && e.lazyMsg.fn == nil {
+					dst = appendString(dst, e.lazyMsg.format)
+				} else {
+					dst = appendString(dst, e.Message())
+				}
+			case messageTemplater:
+				dst = appendString(dst, e.MessageTemplate().Template)
+			default:
+				// the full message is more specific than the message of the
+				// single error, which is good enough for a cache key
+				dst = appendString(dst, uerr.Error())
+			}
+		}
+
+		var callers []uintptr
+		if cerr, ok := uerr.(callerser); ok {
+			callers = appendCallersOf(pcs[:0], cerr)
+		}
+		dst = binary.AppendUvarint(dst, uint64(len(callers)))
+		dst = append(dst, unsafe.Slice(
+			(*byte)(unsafe.Pointer(unsafe.SliceData(callers))),
+			len(callers)*int(unsafe.Sizeof(uintptr(0))),
+		)...)
+	}
+	return dst, true
+}
+
+// Snapshot returns the groups that are currently tracked, ordered by the time
+// they were first seen.
+func (a *Aggregator) Snapshot() []AggregatedError {
+	a.mu.Lock()
+	snapshot := make([]AggregatedError, 0, len(a.groups))
+	for _, elem := range a.groups {
+		snapshot = append(snapshot, elem.Value.(*aggregatedGroup).AggregatedError) //nolint:revive
+	}
+	a.mu.Unlock()
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].FirstSeen.Equal(snapshot[j].FirstSeen) {
+			return snapshot[i].Fingerprint < snapshot[j].Fingerprint
+		}
+		return snapshot[i].FirstSeen.Before(snapshot[j].FirstSeen)
+	})
+	return snapshot
+}
+
+// Reset drops all groups.
+func (a *Aggregator) Reset() {
+	a.mu.Lock()
+	clear(a.groups)
+	a.lru.Init()
+	clear(a.keys)
+	a.keyLRU.Init()
+	a.mu.Unlock()
+}
@@ -0,0 +1,191 @@
+package bruh_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+// fakeClock is a manually advanced clock for the aggregator.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+//go:noinline
+func newAggregatorError(msg string) error {
+	return bruh.Wrap(bruh.New(msg), "calling service")
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	var reports []*bruh.AggregatedReport
+	agg := bruh.NewAggregator(bruh.AggregatorOptions{
+		Interval:  time.Minute,
+		Formatter: bruh.BruhStackedFormatter,
+		Now:       clock.Now,
+		OnReport: func(report *bruh.AggregatedReport) {
+			reports = append(reports, report)
+		},
+	})
+
+	// the errors are created at the same call site, so that they have the same
+	// stack
+	steps := []struct {
+		advance time.Duration
+		msg     string
+	}{
+		{0, "unavailable"},
+		{time.Second, "unavailable"},
+		{0, "unavailable"},
+		{0, "timeout"},
+		{time.Minute, "unavailable"},
+	}
+	forwarded := make([]bool, 0, len(steps))
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		forwarded = append(forwarded, agg.Add(newAggregatorError(step.msg)))
+	}
+	assert.False(agg.Add(nil))
+
+	assert.Equal([]bool{true, false, false, true, true}, forwarded)
+	assert.Len(reports, 3)
+	assert.Equal("calling service: unavailable", reports[0].Message)
+	assert.Equal(int64(1), reports[0].Count)
+	assert.Equal(int64(0), reports[0].Suppressed)
+	assert.Equal(bruh.StringFormat(reports[0].Err, bruh.BruhStackedFormatter), reports[0].Formatted)
+	assert.True(strings.HasPrefix(reports[0].Formatted, "calling service\n"), reports[0].Formatted)
+	assert.Equal("calling service: timeout", reports[1].Message)
+	assert.Equal(int64(4), reports[2].Count)
+	assert.Equal(int64(2), reports[2].Suppressed)
+	assert.Equal(start, reports[2].FirstSeen)
+	assert.Equal(start.Add(time.Minute+time.Second), reports[2].LastSeen)
+	assert.Equal(reports[0].Fingerprint, reports[2].Fingerprint)
+
+	snapshot := agg.Snapshot()
+	assert.Len(snapshot, 2)
+	assert.Equal("calling service: unavailable", snapshot[0].Message)
+	assert.Equal(int64(4), snapshot[0].Count)
+	assert.Equal("calling service: timeout", snapshot[1].Message)
+	assert.Equal(int64(1), snapshot[1].Count)
+	assert.Equal(start.Add(time.Second), snapshot[1].FirstSeen)
+
+	agg.Reset()
+	assert.Len(agg.Snapshot(), 0)
+}
+
+func TestAggregatorMaxGroups(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	agg := bruh.NewAggregator(bruh.AggregatorOptions{MaxGroups: 2, Now: clock.Now})
+	for _, msg := range []string{"a", "b", "a", "c"} {
+		clock.Advance(time.Second)
+		agg.Add(newAggregatorError(msg))
+	}
+	snapshot := agg.Snapshot()
+	assert.Len(snapshot, 2)
+	assert.Equal("calling service: a", snapshot[0].Message)
+	assert.Equal("calling service: c", snapshot[1].Message)
+}
+
+func TestAggregatorConcurrent(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	var (
+		mu       sync.Mutex
+		reported int
+	)
+	agg := bruh.NewAggregator(bruh.AggregatorOptions{
+		Interval: time.Hour,
+		OnReport: func(*bruh.AggregatedReport) {
+			mu.Lock()
+			reported++
+			mu.Unlock()
+		},
+	})
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				agg.Add(newAggregatorError("unavailable"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(1, reported)
+	snapshot := agg.Snapshot()
+	assert.Len(snapshot, 1)
+	assert.Equal(int64(800), snapshot[0].Count)
+}
+
+//go:noinline
+func newAggregatorErrorf(id int) error {
+	return bruh.Wrap(bruh.Errorf("user %d not found", id), "calling service")
+}
+
+func TestAggregatorCachedFingerprints(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	// each kind of error is created twice at the same call sites
+	newError := func(kind, id int) error {
+		switch kind {
+		case 0:
+			// same template and stack, so the cached fingerprint is used
+			return newAggregatorErrorf(id)
+		case 1:
+			// the cache key of foreign errors includes their messages
+			return fmt.Errorf("foreign: %w", newAggregatorErrorf(id))
+		default:
+			// error trees are not cached
+			return bruh.Join(newAggregatorErrorf(id), newAggregatorError("unavailable"))
+		}
+	}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	agg := bruh.NewAggregator(bruh.AggregatorOptions{Interval: time.Hour, Now: clock.Now})
+	errs := make([]error, 0, 6)
+	for kind := range 3 {
+		for id := range 2 {
+			clock.Advance(time.Second)
+			err := newError(kind, id)
+			errs = append(errs, err)
+			agg.Add(err)
+		}
+	}
+
+	snapshot := agg.Snapshot()
+	assert.Len(snapshot, 3)
+	for i, s := range snapshot {
+		assert.Equal(bruh.Fingerprint(errs[2*i]), s.Fingerprint)
+		assert.Equal(bruh.Fingerprint(errs[2*i+1]), s.Fingerprint)
+		assert.Equal(bruh.Message(errs[2*i]), s.Message)
+		assert.Equal(int64(2), s.Count)
+	}
+}
@@ -0,0 +1,215 @@
+package bruh
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// crashWatcherEnv is the environment variable that marks the watcher
+// subprocess started by [InstallCrashHandler].
+const crashWatcherEnv = "BRUH_CRASH_WATCHER"
+
+// crashOutputExt and crashRenderedExt are the file extensions of the raw and the
+// re-rendered crash output.
+const (
+	crashOutputExt   = ".crash"
+	crashRenderedExt = ".log"
+)
+
+// CrashHandlerOptions configures the crash handler installed by
+// [InstallCrashHandler].
+type CrashHandlerOptions struct {
+	// Dir is the directory in which the crash output is stored. It is created
+	// if it doesn't exist. Dir is required, unless Watch is enabled and OnCrash
+	// is set. Processes running at the same time must not share a directory.
+	Dir string
+	// Formatter is used to re-render the crashes. Defaults to
+	// [BruhStackedFormatter].
+	Formatter Formatter
+	// OnCrash is called for every crash. If it is nil, the re-rendered crash is
+	// written to a file in Dir instead.
+	OnCrash func(crash *Crash)
+	// Watch enables the watcher mode. Instead of writing the crash output to a
+	// file and processing it on the next start, the crash output is sent to a
+	// watcher subprocess, which processes it right away.
+	Watch bool
+}
+
+// Crash is a crash of the process that was caught by the crash handler.
+type Crash struct {
+	// Output is the raw crash output of the Go runtime.
+	Output []byte
+	// Panic is the parsed crash output. It is nil if the output could not be
+	// parsed.
+	Panic *Panic
+	// Formatted is the crash re-rendered with the configured formatter. If
+	// the output could not be parsed, it is the raw output.
+	Formatted string
+	// Path is the path of the file that contained the raw crash output. It is
+	// empty in watcher mode.
+	Path string
+}
+
+// InstallCrashHandler installs a process-wide crash handler using
+// [debug.SetCrashOutput]. Unlike [NewFromPanic], it also catches panics that
+// are not recovered, in any goroutine, as well as fatal errors of the Go
+// runtime. The runtime still prints the crash to stderr as usual, but also tees
+// it to the crash handler.
+//
+// By default, the crash output is written to a file in [CrashHandlerOptions.Dir].
+// On the next start, InstallCrashHandler re-renders the crashes of previous
+// runs with the configured [Formatter] and either writes them to Dir or hands
+// them to [CrashHandlerOptions.OnCrash].
+//
+// In watcher mode, the executable is started a second time as a watcher
+// subprocess that receives the crash output through a pipe. It must therefore
+// call InstallCrashHandler at the very beginning of main: In the watcher
+// subprocess, InstallCrashHandler processes the crash output and exits the
+// process instead of returning.
+func InstallCrashHandler(opts CrashHandlerOptions) error {
+	if opts.Formatter == nil {
+		opts.Formatter = BruhStackedFormatter
+	}
+	if os.Getenv(crashWatcherEnv) != "" {
+		runCrashWatcher(opts)
+	}
+	if opts.Dir == "" && (!opts.Watch || opts.OnCrash == nil) {
+		return New("crash handler requires a directory")
+	}
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+			return Wrap(err, "creating crash directory")
+		}
+		if err := handlePreviousCrashes(opts); err != nil {
+			return err
+		}
+	}
+	if opts.Watch {
+		return startCrashWatcher()
+	}
+
+	// tee the crash output into a file of this process
+	name := "crash-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "-" +
+		strconv.Itoa(os.Getpid()) + crashOutputExt
+	f, err := os.Create(filepath.Join(opts.Dir, name))
+	if err != nil {
+		return Wrap(err, "creating crash output file")
+	}
+	defer f.Close()
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		return Wrap(err, "setting crash output")
+	}
+	return nil
+}
+
+// handlePreviousCrashes processes the crash output files of previous runs. Empty
+// files of runs that didn't crash are removed.
+func handlePreviousCrashes(opts CrashHandlerOptions) error {
+	paths, err := filepath.Glob(filepath.Join(opts.Dir, "crash-*"+crashOutputExt))
+	if err != nil {
+		return Wrap(err, "listing crash output files")
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		output, err := os.ReadFile(path)
+		if err != nil {
+			return Wrapf(err, "reading crash output '%s'", path)
+		}
+		if len(bytes.TrimSpace(output)) > 0 {
+			crash := newCrash(output, opts.Formatter)
+			crash.Path = path
+			if err := handleCrash(crash, opts); err != nil {
+				return err
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return Wrapf(err, "removing crash output '%s'", path)
+		}
+	}
+	return nil
+}
+
+// startCrashWatcher starts the watcher subprocess and sends the crash output to
+// it.
+func startCrashWatcher() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return Wrap(err, "getting executable")
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return Wrap(err, "creating crash output pipe")
+	}
+	defer w.Close()
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), crashWatcherEnv+"=1")
+	cmd.Stdin = r
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	err = cmd.Start()
+	_ = r.Close()
+	if err != nil {
+		return Wrap(err, "starting crash watcher")
+	}
+	go func() { _ = cmd.Wait() }()
+	if err := debug.SetCrashOutput(w, debug.CrashOptions{}); err != nil {
+		return Wrap(err, "setting crash output")
+	}
+	return nil
+}
+
+// runCrashWatcher reads the crash output of the parent process from stdin and
+// processes it. It exits the process when done.
+func runCrashWatcher(opts CrashHandlerOptions) {
+	output, err := io.ReadAll(os.Stdin)
+	if err == nil && len(bytes.TrimSpace(output)) > 0 {
+		err = handleCrash(newCrash(output, opts.Formatter), opts)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bruh crash watcher:", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// newCrash parses and re-renders the given crash output.
+func newCrash(output []byte, f Formatter) *Crash {
+	crash := &Crash{Output: output, Formatted: string(output)}
+	if p, err := ParsePanic(bytes.NewReader(output)); err == nil {
+		crash.Panic = p
+		crash.Formatted = StringFormat(p.Err(), f)
+	}
+	return crash
+}
+
+// handleCrash hands the crash to the callback or writes it to the crash
+// directory.
+func handleCrash(crash *Crash, opts CrashHandlerOptions) error {
+	if opts.OnCrash != nil {
+		opts.OnCrash(crash)
+		return nil
+	}
+	var path string
+	if crash.Path != "" {
+		path = strings.TrimSuffix(crash.Path, crashOutputExt) + crashRenderedExt
+	} else {
+		path = filepath.Join(opts.Dir, "crash-"+strconv.FormatInt(time.Now().UnixNano(), 10)+crashRenderedExt)
+	}
+	// write to a temporary file first, so that a complete file appears at once
+	if err := os.WriteFile(path+".tmp", []byte(crash.Formatted+"\n"), 0o644); err != nil { //nolint:gosec
+		return Wrapf(err, "writing crash '%s'", path)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return Wrapf(err, "writing crash '%s'", path)
+	}
+	return nil
+}
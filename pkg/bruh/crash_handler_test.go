@@ -0,0 +1,124 @@
+package bruh_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+//go:noinline
+func crashingGoroutine() {
+	panic("crash in goroutine")
+}
+
+// TestCrashHandlerChild is executed in a child process by the crash handler
+// tests. It installs the crash handler and crashes.
+func TestCrashHandlerChild(t *testing.T) {
+	dir := os.Getenv("BRUH_TEST_CRASH_DIR")
+	if dir == "" {
+		t.Skip("only executed as child process")
+	}
+	err := bruh.InstallCrashHandler(bruh.CrashHandlerOptions{
+		Dir:   dir,
+		Watch: os.Getenv("BRUH_TEST_CRASH_WATCH") != "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go crashingGoroutine()
+	select {}
+}
+
+// runCrashingChild runs [TestCrashHandlerChild] in a child process.
+func runCrashingChild(t *testing.T, dir string, watch bool) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCrashHandlerChild$")
+	cmd.Env = append(os.Environ(), "BRUH_TEST_CRASH_DIR="+dir)
+	if watch {
+		cmd.Env = append(cmd.Env, "BRUH_TEST_CRASH_WATCH=1")
+	}
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected child process to crash, got output:\n%s", output)
+	}
+	if !strings.Contains(string(output), "panic: crash in goroutine") {
+		t.Fatalf("expected crash output, got:\n%s", output)
+	}
+}
+
+func assertCrashLog(t *testing.T, log string) {
+	t.Helper()
+	assert := testutils.NewAssert(t)
+	assert.True(strings.HasPrefix(log, "crash in goroutine\n"), "unexpected crash log:\n"+log)
+	assert.True(strings.Contains(log, "bruh_test.crashingGoroutine ("), "unexpected crash log:\n"+log)
+}
+
+func TestInstallCrashHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OnCrash", func(t *testing.T) {
+		t.Parallel()
+		assert := testutils.NewAssert(t)
+		dir := t.TempDir()
+		runCrashingChild(t, dir, false)
+
+		var crashes []*bruh.Crash
+		err := bruh.InstallCrashHandler(bruh.CrashHandlerOptions{
+			Dir:     dir,
+			OnCrash: func(crash *bruh.Crash) { crashes = append(crashes, crash) },
+		})
+		assert.NoError(err)
+		assert.Len(crashes, 1)
+		assert.NotNil(crashes[0].Panic)
+		assert.Equal("crash in goroutine", crashes[0].Panic.Message)
+		assert.True(strings.HasPrefix(string(crashes[0].Output), "panic: crash in goroutine"))
+		assertCrashLog(t, crashes[0].Formatted)
+		_, err = os.Stat(crashes[0].Path)
+		assert.True(os.IsNotExist(err), "expected raw crash output to be removed")
+	})
+
+	t.Run("WriteToDir", func(t *testing.T) {
+		t.Parallel()
+		assert := testutils.NewAssert(t)
+		dir := t.TempDir()
+		runCrashingChild(t, dir, false)
+
+		assert.NoError(bruh.InstallCrashHandler(bruh.CrashHandlerOptions{Dir: dir}))
+		logs, err := filepath.Glob(filepath.Join(dir, "*.log"))
+		assert.NoError(err)
+		assert.Len(logs, 1)
+		log, err := os.ReadFile(logs[0])
+		assert.NoError(err)
+		assertCrashLog(t, string(log))
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		t.Parallel()
+		assert := testutils.NewAssert(t)
+		dir := t.TempDir()
+		runCrashingChild(t, dir, true)
+
+		// the watcher processes the crash asynchronously
+		var logs []string
+		for start := time.Now(); len(logs) == 0 && time.Since(start) < 10*time.Second; {
+			time.Sleep(10 * time.Millisecond)
+			logs, _ = filepath.Glob(filepath.Join(dir, "*.log"))
+		}
+		assert.Len(logs, 1)
+		log, err := os.ReadFile(logs[0])
+		assert.NoError(err)
+		assertCrashLog(t, string(log))
+	})
+
+	t.Run("MissingDir", func(t *testing.T) {
+		t.Parallel()
+		assert := testutils.NewAssert(t)
+		assert.Error(bruh.InstallCrashHandler(bruh.CrashHandlerOptions{}))
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"runtime"
+	"slices"
 	"sync"
 
 	"github.com/aisbergg/go-bruh/pkg/bruh/fmthelper"
@@ -23,11 +23,45 @@ type Err struct { //nolint: errname
 	fullMsgOnce sync.Once
 	// err is the wrapped error. It can be nil if there is no wrapped error.
 	err error
-	// stackStore is the store for the stack trace of this error. It is embedded
-	// in the error struct to avoid an additional allocation for the stack
-	// trace.
-	stackStore [MaxErrorStackDepth]uintptr
-	stackSize  int
+	// stack is the stack trace of this error. It is captured according to the
+	// stack capture policy, see [SetStackCapturePolicy], and therefore may be
+	// reduced or empty. If suffix is set, it only contains the innermost frames
+	// that are not shared with the stack of the wrapped error.
+	stack []uintptr
+	// suffix is the wrapped error, whose stack shares its outermost frames with
+	// the stack of this error. The last suffixLen frames of its full stack
+	// complete the stack of this error. Storing only the delta saves memory
+	// for deep error chains.
+	suffix    *Err
+	suffixLen int
+	// kind is the classification of this error. It is empty if the error is
+	// not classified.
+	kind Kind
+	// lazyMsg renders the message of this error, if it is set. The message is
+	// rendered together with the full message and stored in msg.
+	lazyMsg *lazyMessage
+	// suppressed are the errors that were suppressed by this error, see
+	// [AddSuppressed].
+	suppressed []error
+}
+
+// lazyMessage is a message that is rendered on first use.
+type lazyMessage struct {
+	format string
+	args   []any
+	fn     func() string
+	// fullFormat and fullArgs render the full message of the error, if it
+	// embeds the messages of the wrapped errors, see [formatMessage].
+	fullFormat string
+	fullArgs   []any
+}
+
+// render renders the message.
+func (m *lazyMessage) render() string {
+	if m.fn != nil {
+		return m.fn()
+	}
+	return fmt.Sprintf(m.format, m.args...)
 }
 
 // New creates a new [Err] with the given message.
@@ -35,9 +69,15 @@ func New(msg string) error {
 	return NewSkip(1, msg)
 }
 
-// NewFromPanic creates a new [Err] from the given panic value. It is intended
-// to be used in a defer statement to recover from panics and convert them into
-// errors. If the given panic value is nil, nil is returned.
+// NewFromPanic creates a new [PanicErr] from the given panic value. It is
+// intended to be used in a defer statement to recover from panics and convert
+// them into errors. If the given panic value is nil, nil is returned. If the
+// panic value is already an error of this package, it is returned as is.
+//
+// When called while the panic is in flight, i.e. within a deferred function,
+// the stack trace of the error starts at the site of the panic instead of the
+// site of the recovery. The panic site is marked by a frame named `panic`, the
+// same way as in Go's panic output. See also [Recover].
 //
 // Example usage:
 //
@@ -59,32 +99,136 @@ func NewFromPanic(panicValue any) error {
 			// if the panic value is already a bruh error, we can just return it
 			return berr.(error) //nolint:revive
 		}
-		return WrapSkip(err, 3, "panic")
 	}
-	return NewSkip(3, fmt.Sprint(panicValue))
+	return newPanicErr(panicValue, 1)
 }
 
 // NewSkip behaves like [New] but skips the given number of callers when
 // creating a stack trace. You should only use this if you are implementing a
 // new error type on top of [Err].
 func NewSkip(skip int, msg string) *Err {
-	// skips this method, runtime.Callers and user defined number of other
-	// callers
-	berr := &Err{msg: msg}
-	berr.stackSize = runtime.Callers(2+max(skip, 0), berr.stackStore[:])
+	var pcs [maxStackCaptureDepth]uintptr
+	// skips this method and user defined number of other callers
+	n, _ := captureStack(skip, &pcs)
+	berr := newErr(pcs[:n])
+	berr.msg = msg
+	recordError(berr)
+	return berr
+}
+
+// sharedStackSuffix returns the [Err] wrapped by err and the number of
+// outermost frames that its full stack shares with the given stack. If err is
+// not an [Err] or the stacks share no frames, nil and zero are returned.
+func sharedStackSuffix(err error, pcs []uintptr) (*Err, int) {
+	be, ok := err.(baseErrer)
+	if !ok {
+		return nil, 0
+	}
+	inner := be.baseErr()
+	var innerStore [maxStackCaptureDepth]uintptr
+	innerPCs := inner.appendCallers(innerStore[:0], 0)
+	shared := 0
+	for shared < len(pcs) && shared < len(innerPCs) &&
+		pcs[len(pcs)-1-shared] == innerPCs[len(innerPCs)-1-shared] {
+		shared++
+	}
+	if shared == 0 {
+		return nil, 0
+	}
+	// Refer to the error that actually stores the shared frames, so that the
+	// reconstruction doesn't have to walk through errors that would contribute
+	// no frames, e.g. when wrapping repeatedly in the same function.
+	for inner.suffix != nil && shared <= inner.suffixLen {
+		inner = inner.suffix
+	}
+	return inner, shared
+}
+
+// newErr allocates a new [Err] with the given stack. The stack is copied into
+// a store that is allocated together with the error, so that creating an error
+// requires a single allocation. The size of the store is rounded up to the
+// next size class, so that only a few differently sized types are needed. If
+// stack interning is enabled, the error refers to the interned stack instead.
+func newErr(pcs []uintptr) *Err {
+	if len(pcs) > 0 {
+		if in := stackInterning.Load(); in != nil {
+			return &Err{stack: in.intern(pcs)}
+		}
+	}
+	var (
+		berr  *Err
+		store []uintptr
+	)
+	switch n := len(pcs); {
+	case n == 0:
+		return &Err{}
+	case n <= 1:
+		e := &struct {
+			Err
+			store [1]uintptr
+		}{}
+		berr, store = &e.Err, e.store[:]
+	case n <= 8:
+		e := &struct {
+			Err
+			store [8]uintptr
+		}{}
+		berr, store = &e.Err, e.store[:]
+	case n <= 16:
+		e := &struct {
+			Err
+			store [16]uintptr
+		}{}
+		berr, store = &e.Err, e.store[:]
+	case n <= 32:
+		e := &struct {
+			Err
+			store [32]uintptr
+		}{}
+		berr, store = &e.Err, e.store[:]
+	default:
+		berr, store = &Err{}, make([]uintptr, n)
+	}
+	berr.stack = store[:copy(store, pcs)]
 	return berr
 }
 
-// Errorf creates a new [Err] with a formatted message.
+// Errorf creates a new [Err] with a formatted message. The message is formatted
+// on the first call of [Err.Error] or [Err.Message], so that no work is wasted
+// on errors that are handled without ever looking at their message. Therefore,
+// the arguments must not be modified after the error was created.
+//
+// Like [fmt.Errorf], the errors referenced by %w verbs are wrapped and the full
+// message is the same as the one of [fmt.Errorf]. The message of the error
+// itself, as returned by [Err.Message], is only the segment of the format
+// string that doesn't refer to the wrapped errors, so that their messages
+// aren't repeated when the chain is formatted.
+//
+// Example usage:
+//
+//	// same as bruh.Wrapf(err, "reading %s", path)
+//	return bruh.Errorf("reading %s: %w", path, err)
 func Errorf(format string, args ...any) error {
-	return NewSkip(1, fmt.Sprintf(format, args...))
+	return ErrorfSkip(1, format, args...)
 }
 
 // ErrorfSkip behaves like [Errorf] but skips the given number of callers when
 // creating a stack trace. It is intended for implementing custom error types on
 // top of [Err].
 func ErrorfSkip(skip int, format string, args ...any) *Err {
-	return NewSkip(skip+1, fmt.Sprintf(format, args...))
+	errs, msg := formatMessage(format, args)
+	var berr *Err
+	switch len(errs) {
+	case 0:
+		berr = NewSkip(skip+1, "")
+	case 1:
+		berr = WrapSkip(errs[0], skip+1, "")
+	default:
+		berr = NewSkip(skip+1, "")
+		berr.err = &wrapErrors{errs: errs}
+	}
+	berr.lazyMsg = msg
+	return berr
 }
 
 // Wrap wraps the given error by creating a new [Err] with the
@@ -103,32 +247,114 @@ func WrapSkip(err error, skip int, msg string) *Err {
 	if err == nil {
 		return nil
 	}
-	// skips this method, runtime.Callers and user defined number of other
-	// callers
-	berr := &Err{msg: msg, err: err}
-	berr.stackSize = runtime.Callers(2+max(skip, 0), berr.stackStore[:])
+	var pcs [maxStackCaptureDepth]uintptr
+	// skips this method and user defined number of other callers
+	n, complete := captureStack(skip, &pcs)
+	// Only complete stacks end at the same outermost frame as the stack of
+	// the wrapped error and can share it.
+	var (
+		suffix    *Err
+		suffixLen int
+	)
+	if complete {
+		suffix, suffixLen = sharedStackSuffix(err, pcs[:n])
+	}
+	berr := newErr(pcs[:n-suffixLen])
+	berr.msg = msg
+	berr.err = err
+	berr.suffix = suffix
+	berr.suffixLen = suffixLen
+	recordError(berr)
 	return berr
 }
 
 // Wrapf wraps the given error by creating a new [Err] with a
-// formatted message. If the given error is nil, nil is returned.
+// formatted message. If the given error is nil, nil is returned. Like for
+// [Errorf], the message is formatted on first use.
 func Wrapf(err error, format string, args ...any) error {
 	if err == nil {
 		return nil
 	}
-	return WrapSkip(err, 1, fmt.Sprintf(format, args...))
+	return WrapfSkip(err, 1, format, args...)
 }
 
 // WrapfSkip behaves like [Wrapf] but skips the given number of callers when
 // creating a stack trace. You should only use this if you are implementing a
 // custom error type on top of [Err].
 func WrapfSkip(err error, skip int, format string, args ...any) *Err {
-	return WrapSkip(err, skip+1, fmt.Sprintf(format, args...))
+	berr := WrapSkip(err, skip+1, "")
+	if berr != nil {
+		berr.lazyMsg = &lazyMessage{format: format, args: args}
+	}
+	return berr
+}
+
+// WrapFunc wraps the given error by creating a new [Err] whose message is
+// returned by the given function. The function is called on the first call of
+// [Err.Error] or [Err.Message], which makes it suitable for messages that are
+// expensive to build. If the given error is nil, nil is returned.
+//
+// Example usage:
+//
+//	return bruh.WrapFunc(err, func() string {
+//	    return "failed to process request " + req.Dump()
+//	})
+func WrapFunc(err error, fn func() string) error {
+	if err == nil {
+		return nil
+	}
+	return WrapFuncSkip(err, 1, fn)
+}
+
+// WrapFuncSkip behaves like [WrapFunc] but skips the given number of callers
+// when creating a stack trace. You should only use this if you are implementing
+// a custom error type on top of [Err].
+func WrapFuncSkip(err error, skip int, fn func() string) *Err {
+	berr := WrapSkip(err, skip+1, "")
+	if berr != nil && fn != nil {
+		berr.lazyMsg = &lazyMessage{fn: fn}
+	}
+	return berr
+}
+
+// Join returns an error that wraps the given errors and records a stack trace.
+// Any nil error values are discarded. Join returns nil if every value in errs
+// is nil. The error message consists of the messages of the wrapped errors,
+// separated by newlines, the same way as [errors.Join] does it.
+//
+// The returned error implements the `Unwrap() []error` method and thus works
+// with [errors.Is] and [errors.As]. The [Unpacker] renders each of the joined
+// errors as a separate branch.
+func Join(errs ...error) error {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	jerr := &joinError{errs: make([]error, 0, n)}
+	for _, err := range errs {
+		if err != nil {
+			jerr.errs = append(jerr.errs, err)
+		}
+	}
+	var pcs [maxStackCaptureDepth]uintptr
+	// skips this method
+	size, _ := captureStack(0, &pcs)
+	jerr.stack = slices.Clone(pcs[:size])
+	return jerr
 }
 
 // Message returns the single, unformatted message of this error, without the
 // messages of wrapped errors.
 func (e *Err) Message() string {
+	if e.lazyMsg != nil {
+		// the message is rendered along with the full message
+		e.buildErrorMessage(nil)
+	}
 	return e.msg
 }
 
@@ -143,6 +369,19 @@ func (e *Err) Error() string {
 
 func (e *Err) buildErrorMessage(sb *fmthelper.StringBuilder) string {
 	e.fullMsgOnce.Do(func() {
+		if e.lazyMsg != nil {
+			e.msg = e.lazyMsg.render()
+			if e.lazyMsg.fullFormat != "" {
+				e.fullMsg = fmt.Sprintf(e.lazyMsg.fullFormat, e.lazyMsg.fullArgs...)
+				if sb != nil {
+					if sb.Len() > 0 && e.fullMsg != "" {
+						sb.WriteString(": ")
+					}
+					sb.WriteString(e.fullMsg)
+				}
+				return
+			}
+		}
 		if sb == nil {
 			// guess the final size of the message to avoid reallocations later on
 			numBytesToAlloc := 0
@@ -198,13 +437,7 @@ func (e *Err) buildErrorMessage(sb *fmthelper.StringBuilder) string {
 // get a string representation of the error without an stack trace and
 // fmt.Sprintf("%+v", err) with a stack trace included.
 func (e *Err) Format(s fmt.State, verb rune) {
-	var str string
-	if verb == 'v' && s.Flag('+') {
-		str = String(e)
-	} else {
-		str = Message(e)
-	}
-	_, _ = io.WriteString(s, str)
+	formatError(e, s, verb)
 }
 
 // Unwrap returns the result of calling the Unwrap method on err, if err's type
@@ -237,9 +470,43 @@ func (e *Err) StackFrames() Stack {
 // Callers returns the recorded caller stack. It implements Bugsnag's
 // [ErrorWithCallers] interface.
 //
+// Wrapping errors store only the frames of their stack that are not shared
+// with the stack of the wrapped error. For them, the full stack is
+// reconstructed on each call.
+//
 // [ErrorWithCallers]: https://github.com/bugsnag/bugsnag-go/blob/46ba8d9aa46bb1d208bfcf408d0b5cff1fd371ab/v2/errors/error.go#L27-L30
 func (e *Err) Callers() []uintptr {
-	return e.stackStore[:e.stackSize]
+	if e.suffix == nil {
+		return e.stack
+	}
+	return e.appendCallers(make([]uintptr, 0, e.callersLen()), 0)
+}
+
+// callersLen returns the length of the full stack of this error.
+func (e *Err) callersLen() int {
+	return len(e.stack) + e.suffixLen
+}
+
+// appendCallers appends the full stack of this error, starting at the frame
+// with the given index, to dst and returns the extended slice. Unlike
+// [Err.Callers], it doesn't allocate if dst is large enough.
+func (e *Err) appendCallers(dst []uintptr, from int) []uintptr {
+	if from < len(e.stack) {
+		dst = append(dst, e.stack[from:]...)
+		from = 0
+	} else {
+		from -= len(e.stack)
+	}
+	if e.suffix != nil && from < e.suffixLen {
+		dst = e.suffix.appendCallers(dst, e.suffix.callersLen()-e.suffixLen+from)
+	}
+	return dst
+}
+
+// baseErr returns the error itself. It gives access to the [Err] embedded in
+// other error types.
+func (e *Err) baseErr() *Err {
+	return e
 }
 
 // // Implements the redacter interface of github.com/aisbergg/go-redact and allows
@@ -248,11 +515,86 @@ func (e *Err) Callers() []uintptr {
 // 	redacter.Redact(e.err)
 // }
 
+// Kind returns the kind of this error, not considering wrapped errors. It is
+// [KindUnknown] if the error was not created with a kind. Use [KindOf] to get
+// the kind of an error chain.
+func (e *Err) Kind() Kind {
+	return e.kind
+}
+
 // bruhError marks the error as a bruh error. It is used to distinguish between
 // errors created by this package and other errors. It is not intended to be
 // used by users of this package.
 func (e *Err) bruhError() {}
 
+// formatError implements the fmt.Formatter interface for the errors of this
+// package.
+func formatError(err error, s fmt.State, verb rune) {
+	var str string
+	if verb == 'v' && s.Flag('+') {
+		str = String(err)
+	} else {
+		str = Message(err)
+	}
+	_, _ = io.WriteString(s, str)
+}
+
+// -----------------------------------------------------------------------------
+
+// joinError is an [Err] that wraps multiple errors. It is created by [Join].
+type joinError struct { //nolint: errname
+	Err
+	errs []error
+}
+
+// Error returns the messages of the joined errors, separated by newlines.
+func (e *joinError) Error() string {
+	e.fullMsgOnce.Do(func() {
+		if len(e.errs) == 1 {
+			e.fullMsg = e.errs[0].Error()
+			return
+		}
+		sb := fmthelper.New(make([]byte, 0, len(e.errs)*64))
+		for i, err := range e.errs {
+			if i > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(err.Error())
+		}
+		e.fullMsg = sb.String()
+	})
+	return e.fullMsg
+}
+
+// Format implements the fmt.Formatter interface. See [Err.Format] for details.
+func (e *joinError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+// Unwrap returns the joined errors.
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
+
+// Cause returns the joined error itself, because there is no single root
+// cause.
+func (e *joinError) Cause() error {
+	return e
+}
+
+// Stack returns a combined stack trace of the join error and the first
+// branch of the joined errors.
+func (e *joinError) Stack() Stack {
+	stack := *newChainStack()
+	stack = stack[:combinedStack(e, stack)]
+	return stack
+}
+
+// StackFrames is an alias for [*joinError.Stack].
+func (e *joinError) StackFrames() Stack {
+	return e.Stack()
+}
+
 // -----------------------------------------------------------------------------
 //
 // Convenience Functions
@@ -263,7 +605,9 @@ func (e *Err) bruhError() {}
 // contains an [errors.Unwrap] method returning error. Otherwise, Unwrap returns
 // nil.
 //
-// See Go's [errors.Unwrap] for more information.
+// See Go's [errors.Unwrap] for more information. Like [errors.Unwrap], it does
+// not unwrap errors that implement `Unwrap() []error`, such as errors created by
+// [Join] or [errors.Join]. Use [UnwrapAll] to unwrap those.
 func Unwrap(err error) error {
 	if u, ok := err.(unwraper); ok {
 		return u.Unwrap()
@@ -271,6 +615,72 @@ func Unwrap(err error) error {
 	return nil
 }
 
+// UnwrapAll returns the errors wrapped by err. It supports both the
+// `Unwrap() error` and the `Unwrap() []error` method. Nil errors are left out.
+// If err does not wrap any errors, nil is returned. The errors referenced by
+// multiple %w verbs of [Errorf] are all returned.
+func UnwrapAll(err error) []error {
+	switch u := err.(type) {
+	case unwraper:
+		if werr, ok := u.Unwrap().(*wrapErrors); ok {
+			return werr.errs
+		}
+		if uerr := u.Unwrap(); uerr != nil {
+			return []error{uerr}
+		}
+	case multiUnwraper:
+		errs := make([]error, 0, len(u.Unwrap()))
+		for _, uerr := range u.Unwrap() {
+			if uerr != nil {
+				errs = append(errs, uerr)
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+	}
+	return nil
+}
+
+// unwrapFirst behaves like [Unwrap], but it also follows the first non-nil
+// error of errors implementing `Unwrap() []error`. It is used to determine the
+// primary path through an error tree.
+func unwrapFirst(err error) error {
+	switch u := err.(type) {
+	case unwraper:
+		return u.Unwrap()
+	case multiUnwraper:
+		for _, uerr := range u.Unwrap() {
+			if uerr != nil {
+				return uerr
+			}
+		}
+	}
+	return nil
+}
+
+// treeSize returns the number of errors in the error tree of err.
+func treeSize(err error) int {
+	n := 0
+	for err != nil {
+		n++
+		switch u := err.(type) {
+		case unwraper:
+			err = u.Unwrap()
+		case multiUnwraper:
+			for _, uerr := range u.Unwrap() {
+				if uerr != nil {
+					n += treeSize(uerr)
+				}
+			}
+			return n
+		default:
+			return n
+		}
+	}
+	return n
+}
+
 // Cause returns the root cause of the error, which is defined as the first
 // error in the chain. The original error is returned if it does not implement
 // [errors.Unwrap] and nil is returned if the error is nil.
@@ -341,14 +751,57 @@ type callerser interface {
 	Callers() []uintptr
 }
 
+// baseErrer is implemented by [Err] and all error types that embed it.
+type baseErrer interface {
+	baseErr() *Err
+}
+
+// framer is implemented by errors that carry an already symbolized stack
+// trace instead of program counters, e.g. errors reconstructed from a
+// [Report]. Like [callerser], the stack only belongs to the error itself.
+type framer interface {
+	Frames() Stack
+}
+
+type typeNamer interface {
+	TypeName() string
+}
+
+// goroutiner is implemented by errors that know the goroutine they occurred
+// in, e.g. errors of parsed panics.
+type goroutiner interface {
+	Goroutine() *Goroutine
+}
+
+// fingerprinter is implemented by errors that supply their own parts of the
+// [Fingerprint].
+type fingerprinter interface {
+	FingerprintParts() []string
+}
+
+// kinder is implemented by errors that carry a [Kind].
+type kinder interface {
+	Kind() Kind
+}
+
 type messager interface {
 	Message() string
 }
 
+// messageTemplater is implemented by errors that provide the template of their
+// message, see [MessageTemplate].
+type messageTemplater interface {
+	MessageTemplate() MessageTemplate
+}
+
 type unwraper interface {
 	Unwrap() error
 }
 
+type multiUnwraper interface {
+	Unwrap() []error
+}
+
 type buildErrorMessager interface {
 	buildErrorMessage(sb *fmthelper.StringBuilder)
 }
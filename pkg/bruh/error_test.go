@@ -3,8 +3,11 @@ package bruh_test
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/aisbergg/go-bruh/internal/testutils"
 	"github.com/aisbergg/go-bruh/pkg/bruh"
 )
 
@@ -99,11 +102,15 @@ func TestErrorCreation(t *testing.T) {
 	assertCreation("WrapfNil", bruh.Wrapf(nil, "abc"), "", 0, true)
 	assertCreation("WrapSkipNil", nilError(bruh.WrapSkip(nil, 1, "abc")), "", 0, true)
 	assertCreation("WrapfSkipNil", nilError(bruh.WrapfSkip(nil, 1, "abc")), "", 0, true)
+	assertCreation("WrapFuncNil", bruh.WrapFunc(nil, func() string { return "abc" }), "", 0, true)
+	assertCreation("WrapFuncSkipNil", nilError(bruh.WrapFuncSkip(nil, 1, func() string { return "abc" })), "", 0, true)
 
 	assertCreation("WrapBruhError", bruh.Wrap(bruh.New("abc"), "abc"), "abc", 3, false)
 	assertCreation("WrapSkipBruhError", bruh.WrapSkip(bruh.New("abc"), 1, "abc"), "abc", 2, false)
 	assertCreation("WrapfBruhError", bruh.Wrapf(bruh.New("abc"), "abc"), "abc", 3, false)
 	assertCreation("WrapfSkipBruhError", bruh.WrapfSkip(bruh.New("abc"), 1, "abc"), "abc", 2, false)
+	assertCreation("WrapFuncBruhError", bruh.WrapFunc(bruh.New("abc"), func() string { return "abc" }), "abc", 3, false)
+	assertCreation("WrapFuncSkipBruhError", bruh.WrapFuncSkip(bruh.New("abc"), 1, func() string { return "abc" }), "abc", 2, false)
 
 	assertCreation("WrapGlobalErr", bruh.Wrap(globalErr, "mno"), "mno", 2, false)
 	assertCreation("WrapSkipGlobalErr", bruh.WrapSkip(globalErr, 1, "pqr"), "pqr", 1, false)
@@ -111,6 +118,100 @@ func TestErrorCreation(t *testing.T) {
 	assertCreation("WrapfSkipGlobalErr", bruh.WrapfSkip(globalErr, 1, "%s %d", "vwx", 42), "vwx 42", 1, false)
 }
 
+// countingStringer counts how often it is formatted.
+type countingStringer struct {
+	count atomic.Int32
+}
+
+func (s *countingStringer) String() string {
+	s.count.Add(1)
+	return "value"
+}
+
+func TestLazyMessage(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	arg := &countingStringer{}
+	var calls atomic.Int32
+	err := bruh.WrapFunc(bruh.Wrapf(bruh.Errorf("root %s", arg), "wrapped %s", arg), func() string {
+		calls.Add(1)
+		return "func " + arg.String()
+	})
+	// nothing is formatted until the message is used
+	assert.Equal(int32(0), arg.count.Load())
+	assert.Equal(int32(0), calls.Load())
+
+	// the messages are rendered only once, even if used concurrently
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal("func value: wrapped value: root value", err.Error())
+		}()
+	}
+	wg.Wait()
+	assert.Equal("func value", err.(*bruh.Err).Message())
+	assert.Equal("wrapped value", bruh.Unwrap(err).(*bruh.Err).Message())
+	assert.Equal(int32(1), calls.Load())
+	assert.Equal(int32(3), arg.count.Load())
+
+	// the message alone can be rendered before the full message
+	inner := bruh.Errorf("inner %d", 42)
+	err = bruh.Wrapf(inner, "outer %d", 7)
+	assert.Equal("outer 7", err.(*bruh.Err).Message())
+	assert.Equal("inner 42", inner.(*bruh.Err).Message())
+	assert.Equal("outer 7: inner 42", err.Error())
+}
+
+func TestErrorfWrapVerb(t *testing.T) {
+	t.Parallel()
+	first := errors.New("first")
+	second := bruh.New("second")
+	assertErrorf := func(name, format string, args []any, expMsg string, expWrapped []error) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			err := bruh.Errorf(format, args...)
+			assert.Equal(fmt.Errorf(format, args...).Error(), err.Error())
+			assert.Equal(expMsg, err.(*bruh.Err).Message())
+			for _, wrapped := range expWrapped {
+				assert.True(errors.Is(err, wrapped), "expected %v to wrap %v", err, wrapped)
+			}
+		})
+	}
+
+	assertErrorf("Trailing", "reading %s: %w", []any{"file", first}, "reading file", []error{first})
+	assertErrorf("Only", "%w", []any{second}, "", []error{second})
+	assertErrorf("Leading", "%w: while reading %s", []any{first, "file"}, "while reading file", []error{first})
+	assertErrorf("LeadingSpace", "%w happened while reading %s", []any{first, "file"}, "happened while reading file", []error{first})
+	assertErrorf("TrailingSpace", "reading %s failed %w", []any{"file", first}, "reading file failed", []error{first})
+	assertErrorf("Embedded", "reading %s (%w)", []any{"file", first}, "reading file ()", []error{first})
+	assertErrorf("Flags", "reading: %+w", []any{first}, "reading", []error{first})
+	assertErrorf(
+		"Multiple",
+		"reading %s: %w, closing: %w",
+		[]any{"file", first, second},
+		"reading file, closing",
+		[]error{first, second},
+	)
+	assertErrorf("ExplicitIndex", "%[2]s: %[1]w", []any{first, "reading"}, "reading", []error{first})
+	assertErrorf("Width", "%*d: %w", []any{3, 7, first}, "  7", []error{first})
+	assertErrorf("Percent", "100%%: %w", []any{first}, "100%", []error{first})
+	assertErrorf("NilError", "reading: %w", []any{nil}, "reading: %!w(<nil>)", nil)
+	assertErrorf("NoError", "reading: %w", []any{"file"}, "reading: %!w(string=file)", nil)
+
+	t.Run("UnwrapAndTemplate", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := bruh.Errorf("reading %s: %w", "file", second)
+		assert.Equal(second, bruh.Unwrap(err))
+		tmpl := err.(*bruh.Err).MessageTemplate()
+		assert.Equal("reading %s", tmpl.Template)
+		assert.Len(tmpl.Args, 1)
+	})
+}
+
 func TestNewFromPanic(t *testing.T) {
 	t.Parallel()
 	assertNewFromPanic := func(name string, panicValue any, expectedMessage string, expectedCause error, checkCause, expectNil bool) {
@@ -452,3 +553,74 @@ func TestIs(t *testing.T) {
 		}
 	})
 }
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+	t.Run("AllNil", func(t *testing.T) {
+		if err := bruh.Join(nil, nil); err != nil {
+			t.Fatalf("expected nil value")
+		}
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		err := bruh.Join(errors.New("first"), nil, bruh.New("second"))
+		if err.Error() != "first\nsecond" {
+			t.Errorf("expected message { first\\nsecond } got { %v }", err.Error())
+		}
+		wrapped := bruh.Wrap(err, "context")
+		if wrapped.Error() != "context: first\nsecond" {
+			t.Errorf("expected message { context: first\\nsecond } got { %v }", wrapped.Error())
+		}
+	})
+
+	t.Run("IsAndAs", func(t *testing.T) {
+		target := &withMessage{msg: "external error"}
+		err := bruh.Wrap(bruh.Join(errors.New("first"), target), "context")
+		if !bruh.Is(err, withMessage{msg: "external error"}) {
+			t.Errorf("expected Is to match the joined target")
+		}
+		var as *withMessage
+		if !bruh.As(err, &as) || as != target {
+			t.Errorf("expected As to find the joined target")
+		}
+	})
+
+	t.Run("Stack", func(t *testing.T) {
+		err := bruh.Join(bruh.New("first"), bruh.New("second"))
+		callers := err.(interface{ Callers() []uintptr }).Callers()
+		if len(callers) == 0 {
+			t.Fatalf("expected callers to be recorded")
+		}
+		stack := err.(interface{ Stack() bruh.Stack }).Stack()
+		if len(stack) < 2 {
+			t.Errorf("expected combined stack of the first branch, got %d frames", len(stack))
+		}
+	})
+}
+
+func TestUnwrapAll(t *testing.T) {
+	t.Parallel()
+	first := errors.New("first")
+	second := errors.New("second")
+	assertUnwrapAll := func(name string, err error, exp []error) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			act := bruh.UnwrapAll(err)
+			if len(act) != len(exp) {
+				t.Fatalf("expected %d errors, got %d", len(exp), len(act))
+			}
+			for i := range exp {
+				if act[i] != exp[i] {
+					t.Errorf("expected { %v } at index %d got { %v }", exp[i], i, act[i])
+				}
+			}
+		})
+	}
+
+	assertUnwrapAll("Nil", nil, nil)
+	assertUnwrapAll("NotWrapping", first, nil)
+	assertUnwrapAll("Single", bruh.Wrap(first, "context"), []error{first})
+	assertUnwrapAll("Join", bruh.Join(first, second), []error{first, second})
+	assertUnwrapAll("ErrorsJoin", errors.Join(first, nil, second), []error{first, second})
+	assertUnwrapAll("MultipleW", fmt.Errorf("%w and %w", first, second), []error{first, second})
+}
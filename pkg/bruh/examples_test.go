@@ -1,6 +1,8 @@
 package bruh_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -53,6 +55,76 @@ func ExampleNewFromPanic() {
 	// something went wrong
 }
 
+func ExampleRecover() {
+	process := func() (err error) {
+		defer bruh.Recover(&err)
+		panic("something went wrong")
+	}
+
+	err := process()
+	value, _ := bruh.PanicValue(err)
+	fmt.Println(err)
+	fmt.Println(value)
+
+	// Output:
+	// something went wrong
+	// something went wrong
+}
+
+func ExampleGo() {
+	errc := bruh.Go(func() error {
+		return bruh.New("failed in goroutine")
+	})
+
+	err := <-errc
+	fmt.Println(err)
+
+	// Output:
+	// failed in goroutine
+}
+
+func ExampleKindOf() {
+	err := bruh.Wrap(bruh.NewKind(bruh.KindNotFound, "user not found"), "loading profile")
+	fmt.Println(bruh.KindOf(err))
+
+	// foreign errors are classified by the registry
+	fmt.Println(bruh.KindOf(bruh.Wrap(context.DeadlineExceeded, "calling service")))
+
+	// Output:
+	// NotFound
+	// DeadlineExceeded
+}
+
+func ExampleFingerprint() {
+	opts := bruh.FingerprintOptions{IgnoreMessages: true}
+	fingerprints := make([]string, 0, 2)
+	for _, id := range []int{1, 2} {
+		err := bruh.Errorf("user %d not found", id)
+		fingerprints = append(fingerprints, bruh.Fingerprint(err, opts))
+	}
+	fmt.Println(fingerprints[0] == fingerprints[1])
+
+	// Output:
+	// true
+}
+
+func ExampleNewAggregator() {
+	agg := bruh.NewAggregator(bruh.AggregatorOptions{
+		Interval: time.Minute,
+		OnReport: func(report *bruh.AggregatedReport) {
+			fmt.Printf("forwarded: %s\n", report.Message)
+		},
+	})
+	for range 100 {
+		agg.Add(bruh.New("service unavailable"))
+	}
+	fmt.Println("count:", agg.Snapshot()[0].Count)
+
+	// Output:
+	// forwarded: service unavailable
+	// count: 100
+}
+
 func ExampleNewSkip() {
 	type CustomError struct {
 		bruh.Err
@@ -159,6 +231,17 @@ func ExampleWrapfSkip() {
 	//     at main.main (_testmain.go:123)
 }
 
+func ExampleJoin() {
+	err := bruh.Join(io.ErrUnexpectedEOF, io.ErrClosedPipe)
+	fmt.Println(err)
+	fmt.Println(bruh.Is(err, io.ErrClosedPipe))
+
+	// Output:
+	// unexpected EOF
+	// io: read/write on closed pipe
+	// true
+}
+
 // -----------------------------------------------------------------------------
 // Methods of *bruh.Err
 // -----------------------------------------------------------------------------
@@ -286,6 +369,17 @@ func ExampleUnwrap() {
 	// true
 }
 
+func ExampleUnwrapAll() {
+	err := bruh.Join(io.ErrUnexpectedEOF, io.ErrClosedPipe)
+	for _, uerr := range bruh.UnwrapAll(err) {
+		fmt.Println(uerr)
+	}
+
+	// Output:
+	// unexpected EOF
+	// io: read/write on closed pipe
+}
+
 func ExampleCause() {
 	err := bruh.Wrap(bruh.Wrap(io.ErrUnexpectedEOF, "inner"), "outer")
 	fmt.Println(bruh.Cause(err) == io.ErrUnexpectedEOF)
@@ -324,3 +418,35 @@ func clean(s string) string {
 	s = regexp.MustCompile(`0x[0-9a-fA-F]+`).ReplaceAllLiteralString(s, "0x12345")
 	return s
 }
+
+func ExampleNewReport() {
+	// the report can be sent to another process, e.g. as JSON
+	data, _ := json.Marshal(bruh.NewReport(bruh.Wrap(errors.New("root error"), "wrapped")))
+
+	// the receiving side turns the report back into an error
+	report, _ := bruh.UnmarshalReport(data)
+	err := report.Err()
+	fmt.Println(err)
+	fmt.Println(err.(*bruh.RemoteErr).TypeName())
+	// Output:
+	// wrapped: root error
+	// *bruh.Err
+}
+
+func ExampleParsePanic() {
+	output := `panic: something went wrong
+
+goroutine 1 [running]:
+main.do(...)
+	/app/main.go:12
+main.main()
+	/app/main.go:6 +0x1d
+exit status 2`
+
+	p, _ := bruh.ParsePanic(strings.NewReader(output))
+	fmt.Println(bruh.StringFormat(p.Err(), bruh.BruhStackedFormatter))
+	// Output:
+	// something went wrong
+	//     at main.do (/app/main.go:12)
+	//     at main.main (/app/main.go:6)
+}
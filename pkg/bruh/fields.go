@@ -0,0 +1,149 @@
+package bruh
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/aisbergg/go-bruh/pkg/bruh/fmthelper"
+)
+
+// FieldMapping maps the parts of an error chain to the field names of a log
+// schema. Fields with an empty name are left out. Use one of the presets
+// [ECSFieldMapping] or [OTelFieldMapping] or define a mapping of your own.
+type FieldMapping struct {
+	// Message is the name of the field holding the full message of the chain.
+	Message string
+	// Type is the name of the field holding the type name of the root cause,
+	// see [Cause]. The root cause is used, because the wrapping errors usually
+	// are of the same type, e.g. `*bruh.Err`.
+	Type string
+	// StackTrace is the name of the field holding the stack trace, as produced
+	// by the chosen [Formatter].
+	StackTrace string
+	// Kind is the name of the field holding the kind of the chain, see
+	// [KindOf]. It is left out if the kind is unknown.
+	Kind string
+	// TagsPrefix is prepended to the keys of the tags of the chain (see package
+	// ctxerror).
+	TagsPrefix string
+	// ContextPrefix is prepended to the keys of the context of the chain (see
+	// package ctxerror). A context value is keyed by its group and key, joined
+	// by a dot, e.g. `user.id`.
+	ContextPrefix string
+}
+
+// ECSFieldMapping maps errors to the fields of the Elastic Common Schema (ECS).
+// The tags are stored as ECS labels, the context groups become top-level field
+// sets, e.g. `user.id`.
+var ECSFieldMapping = FieldMapping{
+	Message:    "error.message",
+	Type:       "error.type",
+	StackTrace: "error.stack_trace",
+	Kind:       "error.code",
+	TagsPrefix: "labels.",
+}
+
+// OTelFieldMapping maps errors to the exception attributes of the OpenTelemetry
+// semantic conventions. The kind is stored as `error.type`, which describes the
+// class of an error. Tags and context groups become attributes of their own.
+var OTelFieldMapping = FieldMapping{
+	Message:    "exception.message",
+	Type:       "exception.type",
+	StackTrace: "exception.stacktrace",
+	Kind:       "error.type",
+}
+
+// Fields returns the fields of the given error chain as a flat map, using the
+// field names of the mapping. The stack trace is produced by f; if f is nil,
+// [BruhFormatter] is used. If err is nil, nil is returned.
+//
+// Tags and context of package ctxerror are not included, because this package
+// does not know about them. Use ctxerror.Fields to include them.
+//
+// Example usage:
+//
+//	fields := bruh.Fields(err, bruh.ECSFieldMapping, bruh.JavaStackTraceFormatter)
+func Fields(err error, mapping FieldMapping, f Formatter) map[string]any {
+	if err == nil {
+		return nil
+	}
+	if f == nil {
+		f = BruhFormatter
+	}
+	fields := make(map[string]any, 4)
+	if mapping.Message != "" {
+		fields[mapping.Message] = Message(err)
+	}
+	if mapping.Type != "" {
+		fields[mapping.Type] = typeName(Cause(err))
+	}
+	if mapping.StackTrace != "" {
+		fields[mapping.StackTrace] = StringFormat(err, f)
+	}
+	if kind := KindOf(err); mapping.Kind != "" && kind != KindUnknown {
+		fields[mapping.Kind] = string(kind)
+	}
+	return fields
+}
+
+// AppendFieldsJSON appends the fields as a single-line JSON object to b. The
+// keys are written in sorted order, so that the output is deterministic. Values
+// other than strings, booleans and numbers are encoded with [json.Marshal]; if
+// that fails, their string representation is written instead.
+func AppendFieldsJSON(b []byte, fields map[string]any) []byte {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	builder := fmthelper.New(b)
+	builder.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteJSONString(k)
+		builder.WriteByte(':')
+		switch v := fields[k].(type) {
+		case string:
+			builder.WriteJSONString(v)
+		case bool:
+			builder.WriteString(strconv.FormatBool(v))
+		case int:
+			builder.WriteInt(int64(v))
+		case int64:
+			builder.WriteInt(v)
+		case nil:
+			builder.WriteString("null")
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				builder.WriteJSONString(fmt.Sprint(v))
+				continue
+			}
+			builder.Write(data)
+		}
+	}
+	builder.WriteByte('}')
+	return builder.Bytes()
+}
+
+// NewFieldsFormatter returns a [Formatter] that produces the fields of the
+// error chain (see [Fields]) as a single-line JSON object. The stack trace is
+// produced by f.
+//
+// Example usage:
+//
+//	f := bruh.NewFieldsFormatter(bruh.OTelFieldMapping, bruh.JavaStackTraceFormatter)
+//	fmt.Println(bruh.StringFormat(err, f))
+func NewFieldsFormatter(mapping FieldMapping, f Formatter) Formatter {
+	return func(b []byte, unpacker *Unpacker) []byte {
+		if unpacker.Error() == nil {
+			return b
+		}
+		return AppendFieldsJSON(b, Fields(unpacker.Error(), mapping, f))
+	}
+}
@@ -0,0 +1,66 @@
+package bruh_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+	err := bruh.Wrap(bruh.NewKind(bruh.KindNotFound, "user not found"), "handling request")
+
+	t.Run("ECS", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		fields := bruh.Fields(err, bruh.ECSFieldMapping, bruh.JavaStackTraceFormatter)
+		assert.Len(fields, 4)
+		assert.Equal("handling request: user not found", fields["error.message"])
+		assert.Equal("*bruh.Err", fields["error.type"])
+		assert.Equal("NotFound", fields["error.code"])
+		assert.Equal(bruh.StringFormat(err, bruh.JavaStackTraceFormatter), fields["error.stack_trace"])
+	})
+
+	t.Run("OTel", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		fields := bruh.Fields(bruh.Wrap(errors.New("foreign"), "context"), bruh.OTelFieldMapping, nil)
+		assert.Len(fields, 3)
+		assert.Equal("context: foreign", fields["exception.message"])
+		assert.Equal("*errors.errorString", fields["exception.type"])
+		assert.True(strings.HasPrefix(fields["exception.stacktrace"].(string), "context: foreign\n"))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		assert.True(bruh.Fields(nil, bruh.ECSFieldMapping, nil) == nil)
+		assert.Equal("", bruh.StringFormat(nil, bruh.NewFieldsFormatter(bruh.ECSFieldMapping, nil)))
+	})
+
+	t.Run("Formatter", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		out := bruh.StringFormat(err, bruh.NewFieldsFormatter(bruh.OTelFieldMapping, bruh.JavaStackTraceFormatter))
+		assert.False(strings.Contains(out, "\n"), "expected a single line")
+		var got map[string]any
+		assert.NoError(json.Unmarshal([]byte(out), &got))
+		exp := bruh.Fields(err, bruh.OTelFieldMapping, bruh.JavaStackTraceFormatter)
+		assert.Equal(exp, got)
+	})
+}
+
+func TestAppendFieldsJSON(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+	out := bruh.AppendFieldsJSON([]byte("x"), map[string]any{
+		"s":   "a\"b",
+		"b":   true,
+		"i":   42,
+		"n":   nil,
+		"m":   map[string]int{"k": 1},
+		"bad": func() {},
+	})
+	assert.Equal(`x{"b":true,"bad":"`, string(out[:18]))
+	assert.True(strings.HasSuffix(string(out), `,"i":42,"m":{"k":1},"n":null,"s":"a\"b"}`))
+}
@@ -0,0 +1,115 @@
+package bruh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// FingerprintOptions configures [Fingerprint].
+type FingerprintOptions struct {
+	// IgnoreLines excludes the line numbers of the stack frames, so that the
+	// fingerprint is not changed by unrelated changes of the source files.
+	IgnoreLines bool
+	// IgnoreMessages excludes the messages of the errors, so that errors with
+	// varying messages, e.g. because they contain IDs, are grouped together.
+	IgnoreMessages bool
+	// LocationOnly uses only the first frame of the stack of each error, which
+	// is the location the error was created at. Use it together with a stack
+	// capture policy that captures the full stack only for some errors, e.g.
+	// [StackCaptureSampled], so that the fingerprint doesn't depend on whether
+	// the stack of an error was captured.
+	LocationOnly bool
+}
+
+// Fingerprint returns a deterministic fingerprint of the error chain, which can
+// be used to group and deduplicate errors, similar to how Sentry groups
+// events. Errors of the same origin have the same fingerprint, even if they
+// were created by different processes or builds. If err is nil, an empty
+// string is returned.
+//
+// The fingerprint is built from the elements of the unpacked error, see
+// [Unpack]: the type names, the messages and the locations the errors were
+// created at. For errors that provide a [MessageTemplate], the template is used
+// instead of the message, so that errors whose messages only differ in their
+// arguments are grouped together.
+// The stack of an error is its partial stack, see [UnpackedElement]. Of each
+// frame, only the function name, the base name of the file and the line number
+// are used. Program counters are not used, because they change between builds.
+// Frames of Go's runtime package are left out, because they depend on the Go
+// version. Since the full stack is used, errors created at the same location,
+// but reached through different call paths, have different fingerprints. To
+// only use the location, see [FingerprintOptions.LocationOnly].
+//
+// Errors can supply their own fingerprint parts by implementing
+// `FingerprintParts() []string`. If the method returns a non-nil slice, the
+// parts are used instead of the type name and message of the error. The
+// location is still included.
+func Fingerprint(err error, opts ...FingerprintOptions) string {
+	if err == nil {
+		return ""
+	}
+	var o FingerprintOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	unpacker := newUnpacker(err, false)
+	upkErr := unpacker.Unpack()
+
+	h := sha256.New()
+	buf := make([]byte, 0, 256)
+	for i := range upkErr {
+		upkElm := &upkErr[i]
+		buf = append(buf[:0], 'e')
+		buf = strconv.AppendInt(buf, int64(upkElm.Parent), 10)
+		var parts []string
+		if fp, ok := upkElm.Err.(fingerprinter); ok {
+			parts = fp.FingerprintParts()
+		}
+		if parts != nil {
+			for _, part := range parts {
+				buf = append(buf, 0)
+				buf = append(buf, part...)
+			}
+		} else {
+			buf = append(buf, 0)
+			buf = append(buf, typeName(upkElm.Err)...)
+			if !o.IgnoreMessages {
+				buf = append(buf, 0)
+				if mt, ok := upkElm.Err.(messageTemplater); ok {
+					buf = append(buf, mt.MessageTemplate().Template...)
+				} else {
+					buf = append(buf, upkElm.Msg...)
+				}
+			}
+		}
+		buf = append(buf, '\n')
+		stack := upkElm.PartialStack
+		// the partial stack starts with the same frame as the full one
+		if o.LocationOnly && len(stack) > 1 {
+			stack = stack[:1]
+		}
+		for j := range stack {
+			frame := &stack[j]
+			if strings.HasPrefix(frame.Name, "runtime.") {
+				continue
+			}
+			buf = append(buf, 'f')
+			buf = append(buf, frame.Name...)
+			buf = append(buf, 0)
+			buf = append(buf, path.Base(frame.File)...)
+			if !o.IgnoreLines {
+				buf = append(buf, 0)
+				buf = strconv.AppendInt(buf, int64(frame.Line), 10)
+			}
+			buf = append(buf, '\n')
+		}
+		_, _ = h.Write(buf)
+	}
+	disposeUnpacker(unpacker)
+
+	var sum [sha256.Size]byte
+	return hex.EncodeToString(h.Sum(sum[:0])[:16])
+}
@@ -0,0 +1,141 @@
+package bruh_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+// fingerprintTestError is an error that supplies its own fingerprint parts.
+type fingerprintTestError struct {
+	id int
+}
+
+func (e fingerprintTestError) Error() string { return fmt.Sprintf("record %d is locked", e.id) }
+
+func (fingerprintTestError) FingerprintParts() []string { return []string{"record locked"} }
+
+//go:noinline
+func newFingerprintError(msg string) error {
+	return bruh.Wrap(bruh.New(msg), "wrapped")
+}
+
+//go:noinline
+func newFingerprintErrorOtherLine(msg string) error {
+	return bruh.Wrap(
+		bruh.New(msg),
+		"wrapped",
+	)
+}
+
+// newFingerprintErrorFromHandler creates the same error as
+// [newFingerprintError], but through a different call path.
+//
+//go:noinline
+func newFingerprintErrorFromHandler(msg string) error {
+	return newFingerprintError(msg)
+}
+
+// fingerprints returns the fingerprints of errors created by newErr with the
+// given messages. The errors are created at the same call site, so that they
+// have the same stack.
+func fingerprints(newErr func(msg string) error, opts bruh.FingerprintOptions, msgs ...string) []string {
+	fps := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		fps = append(fps, bruh.Fingerprint(newErr(msg), opts))
+	}
+	return fps
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Nil", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		assert.Equal("", bruh.Fingerprint(nil))
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		fps := fingerprints(newFingerprintError, bruh.FingerprintOptions{}, "failed", "failed", "other")
+		assert.Len(fps[0], 32)
+		assert.Equal(fps[0], fps[1])
+		assert.True(fps[0] != fps[2])
+		assert.True(fps[0] != bruh.Fingerprint(bruh.New("failed")))
+	})
+
+	t.Run("IgnoreLines", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		newErr := func(msg string) error {
+			if msg == "other line" {
+				return newFingerprintErrorOtherLine("failed")
+			}
+			return newFingerprintError("failed")
+		}
+		fps := fingerprints(newErr, bruh.FingerprintOptions{}, "", "other line")
+		assert.True(fps[0] != fps[1])
+		// the function names still differ
+		fps = fingerprints(newErr, bruh.FingerprintOptions{IgnoreLines: true}, "", "", "other line")
+		assert.Equal(fps[0], fps[1])
+		assert.True(fps[0] != fps[2])
+	})
+
+	t.Run("CallPath", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		newErr := func(msg string) error {
+			if msg == "handler" {
+				return newFingerprintErrorFromHandler("failed")
+			}
+			return newFingerprintError("failed")
+		}
+		fps := fingerprints(newErr, bruh.FingerprintOptions{}, "", "handler")
+		assert.True(fps[0] != fps[1])
+		// the errors are created at the same location
+		fps = fingerprints(newErr, bruh.FingerprintOptions{LocationOnly: true}, "", "handler")
+		assert.Equal(fps[0], fps[1])
+	})
+
+	t.Run("IgnoreMessages", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		fps := fingerprints(newFingerprintError, bruh.FingerprintOptions{IgnoreMessages: true}, "user 1 not found", "user 2 not found")
+		assert.Equal(fps[0], fps[1])
+	})
+
+	t.Run("MessageTemplate", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		newErr := func(msg string) error {
+			return bruh.Wrapf(bruh.Errorf("user %s not found", msg), "wrapped %d", len(msg))
+		}
+		fps := fingerprints(newErr, bruh.FingerprintOptions{}, "a", "bb")
+		assert.Equal(fps[0], fps[1])
+	})
+
+	t.Run("FingerprintParts", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		newErr := func(msg string) error {
+			return bruh.Wrap(fingerprintTestError{id: len(msg)}, "wrapped")
+		}
+		fps := fingerprints(newErr, bruh.FingerprintOptions{}, "a", "bb")
+		assert.Equal(fps[0], fps[1])
+		fps = fingerprints(func(msg string) error { return errors.New(msg) }, bruh.FingerprintOptions{}, "a", "bb")
+		assert.True(fps[0] != fps[1])
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := newFingerprintError("failed")
+		report := bruh.NewReport(err)
+		assert.Equal(bruh.Fingerprint(err), report.Fingerprint)
+
+		data, jerr := json.Marshal(report)
+		assert.NoError(jerr)
+		var decoded bruh.Report
+		assert.NoError(json.Unmarshal(data, &decoded))
+		assert.Equal(report.Fingerprint, decoded.Fingerprint)
+		assert.Equal(report.Fingerprint, bruh.Fingerprint(decoded.Err()))
+	})
+}
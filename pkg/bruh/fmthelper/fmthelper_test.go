@@ -1,6 +1,8 @@
 package fmthelper
 
 import (
+	"encoding/json"
+	"html"
 	"testing"
 
 	"github.com/aisbergg/go-bruh/internal/testutils"
@@ -86,6 +88,38 @@ func TestStringBuilder(t *testing.T) {
 
 		assert.Equal("-42|ff|42|ff", builder.String())
 	})
+
+	t.Run("WriteJSONString", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		for _, s := range []string{
+			"",
+			"plain",
+			"quote \" and backslash \\",
+			"new\nline\r\ttab \x00\x1f",
+			"unicode äöü 🙂 \u2028\u2029",
+			"invalid \xff utf-8",
+		} {
+			builder := New(nil)
+			builder.WriteJSONString(s)
+			exp, err := json.Marshal(s)
+			assert.NoError(err)
+			assert.Equal(string(exp), builder.String())
+		}
+	})
+
+	t.Run("WriteHTMLString", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		for _, s := range []string{
+			"",
+			"plain",
+			"<script>alert('x' && \"y\")</script>",
+			"unicode äöü 🙂 &amp;",
+		} {
+			builder := New(nil)
+			builder.WriteHTMLString(s)
+			assert.Equal(html.EscapeString(s), builder.String())
+		}
+	})
 }
 
 func TestColorer(t *testing.T) {
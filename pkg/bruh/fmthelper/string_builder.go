@@ -3,6 +3,7 @@ package fmthelper
 import (
 	"strconv"
 	"strings"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -106,3 +107,82 @@ func (b *StringBuilder) WriteUint(value uint64) {
 func (b *StringBuilder) WriteUintAsHex(value uint64) {
 	b.buf = strconv.AppendUint(b.buf, value, 16)
 }
+
+// WriteJSONString appends s as a quoted JSON string to b's buffer. Quotes,
+// backslashes and control characters are escaped, as are the line and
+// paragraph separators U+2028 and U+2029, which JavaScript doesn't allow in
+// string literals. Invalid UTF-8 is replaced by the Unicode replacement
+// character.
+func (b *StringBuilder) WriteJSONString(s string) {
+	const hex = "0123456789abcdef"
+	b.buf = append(b.buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b.buf = append(b.buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b.buf = append(b.buf, '\\', c)
+			case '\n':
+				b.buf = append(b.buf, '\\', 'n')
+			case '\r':
+				b.buf = append(b.buf, '\\', 'r')
+			case '\t':
+				b.buf = append(b.buf, '\\', 't')
+			default:
+				b.buf = append(b.buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.buf = append(b.buf, s[start:i]...)
+			b.buf = utf8.AppendRune(b.buf, utf8.RuneError)
+		case r == '\u2028' || r == '\u2029':
+			b.buf = append(b.buf, s[start:i]...)
+			b.buf = append(b.buf, '\\', 'u', '2', '0', '2', hex[r&0xf])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	b.buf = append(b.buf, s[start:]...)
+	b.buf = append(b.buf, '"')
+}
+
+// WriteHTMLString appends s to b's buffer, escaping the characters that have a
+// special meaning in HTML text and attribute values: `<`, `>`, `&`, `'` and
+// `"`. The output is the same as that of [html.EscapeString].
+func (b *StringBuilder) WriteHTMLString(s string) {
+	start := 0
+	for i := range len(s) {
+		var esc string
+		switch s[i] {
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '&':
+			esc = "&amp;"
+		case '\'':
+			esc = "&#39;"
+		case '"':
+			esc = "&#34;"
+		default:
+			continue
+		}
+		b.buf = append(b.buf, s[start:i]...)
+		b.buf = append(b.buf, esc...)
+		start = i + 1
+	}
+	b.buf = append(b.buf, s[start:]...)
+}
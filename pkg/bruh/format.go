@@ -2,7 +2,10 @@ package bruh
 
 import (
 	"reflect"
+	"strings"
 	"unsafe"
+
+	"github.com/aisbergg/go-bruh/pkg/bruh/fmthelper"
 )
 
 // Formatter turns an unpacked error into a formatted string.
@@ -114,5 +117,41 @@ func typeName(err error) string {
 	if _, ok := err.(*Err); ok {
 		return "*bruh.Err"
 	}
+	if tn, ok := err.(typeNamer); ok {
+		return tn.TypeName()
+	}
 	return reflect.TypeOf(err).String()
 }
+
+// writeSuppressed writes the given suppressed errors, see [AddSuppressed], each
+// on a new line and formatted by f. The first line of each error is prefixed
+// with prefix and label, all other lines with prefix.
+func writeSuppressed(
+	builder *fmthelper.StringBuilder,
+	unpacker *Unpacker,
+	suppressed []error,
+	f Formatter,
+	prefix, label string,
+) {
+	for _, serr := range suppressed {
+		builder.WriteByte('\n')
+		b := AppendStringFormat(nil, serr, f, unpacker.unpackAll)
+		writePrefixed(builder, unsafe.String(unsafe.SliceData(b), len(b)), prefix+label, prefix)
+	}
+}
+
+// writePrefixed writes s line by line. The first line is prefixed with
+// firstPrefix, all other lines with prefix.
+func writePrefixed(builder *fmthelper.StringBuilder, s, firstPrefix, prefix string) {
+	builder.WriteString(firstPrefix)
+	for {
+		idx := strings.IndexByte(s, '\n')
+		if idx < 0 {
+			builder.WriteString(s)
+			return
+		}
+		builder.WriteString(s[:idx+1])
+		builder.WriteString(prefix)
+		s = s[idx+1:]
+	}
+}
@@ -16,6 +16,19 @@ import (
 //	    at function1 (file1:line1)
 //	    at function2 (file2:line2)
 //	    at functionN (fileN:lineN)
+//
+// For errors of goroutines started with [Go] or parsed by [ParsePanic], the
+// stack of the creating goroutine is appended:
+//
+//	created by goroutine 1
+//	    at function3 (file3:line3)
+//	    at function4 (file4:line4)
+//
+// Suppressed errors of the chain, see [AddSuppressed], are appended, each
+// formatted the same way:
+//
+//	suppressed: errorMsg5
+//	    at function5 (file5:line5)
 func BruhFormatter( //nolint:revive // we keep the "Bruh" prefix to differentiate from the other formatters
 	b []byte,
 	unpacker *Unpacker,
@@ -82,6 +95,24 @@ func BruhFancyFormatter( //nolint:revive // we keep the "Bruh" prefix to differe
 //	    at function2 (file2:line2)
 //	    at functionN (fileN:lineN)
 //	externalErrorMsg
+//
+// If an error wraps multiple errors (error tree), each of the wrapped errors is
+// rendered as a numbered and indented branch:
+//
+//	errorMsg1
+//	    at function1 (file1:line1)
+//	    #0: errorMsg2
+//	        at function2 (file2:line2)
+//	    #1: errorMsg3
+//	        at function3 (file3:line3)
+//
+// Suppressed errors, see [AddSuppressed], are indented below the stack of the
+// error that suppressed them:
+//
+//	errorMsg1
+//	    at function1 (file1:line1)
+//	    suppressed: errorMsg5
+//	        at function5 (file5:line5)
 func BruhStackedFormatter( //nolint:revive // we keep the "Bruh" prefix to differentiate from the other formatters
 	b []byte,
 	unpacker *Unpacker,
@@ -124,6 +155,9 @@ func BruhStackedFormatter( //nolint:revive // we keep the "Bruh" prefix to diffe
 //	    at functionN (fileN:lineN)
 //	typeNameN: externalErrorMsg
 //
+// Errors with a [Kind] are annotated with the kind in brackets, e.g.
+// `*bruh.Err [NotFound]: errorMsg1`.
+//
 // With "sourced" enabled and source code available:
 //
 //	errorMsg1
@@ -193,6 +227,9 @@ func formatBruhStacked(b []byte, unpacker *Unpacker, colored, sourced, typed boo
 	}
 	builder.Grow(guessCap)
 	colorer := fmthelper.NewColorer(builder, colored)
+	self := func(b []byte, unpacker *Unpacker) []byte {
+		return formatBruhStacked(b, unpacker, colored, sourced, typed)
+	}
 
 	lastIndex := len(upkErr) - 1
 	for i, upkElm := range upkErr {
@@ -200,8 +237,15 @@ func formatBruhStacked(b []byte, unpacker *Unpacker, colored, sourced, typed boo
 		if msg == "" {
 			msg = "<no message>"
 		}
+		indent := branchIndent(upkErr, i)
+		builder.WriteString(indent)
+		if upkErr.IsBranch(i) {
+			builder.WriteByte('#')
+			builder.WriteInt(int64(branchIndex(upkErr, i)))
+			builder.WriteString(": ")
+		}
 		if typed {
-			colorer.ColoredText(typeName(upkElm.Err), fmthelper.Bold, fmthelper.BrightRed)
+			colorer.ColoredText(typeNameWithKind(upkElm.Err), fmthelper.Bold, fmthelper.BrightRed)
 			colorer.Color(fmthelper.Bold)
 			builder.WriteString(": ")
 			builder.WriteString(msg)
@@ -214,11 +258,13 @@ func formatBruhStacked(b []byte, unpacker *Unpacker, colored, sourced, typed boo
 				builder.WriteString("\n")
 			}
 			for j, s := range upkElm.PartialStack {
-				formatSingleStackWithSourceCode(s, sourceLines[i][j], builder, colorer)
+				formatSingleStackWithSourceCode(s, sourceLines[i][j], indent, builder, colorer)
 			}
 		} else {
 			for _, s := range upkElm.PartialStack {
-				builder.WriteString("\n    at ")
+				builder.WriteByte('\n')
+				builder.WriteString(indent)
+				builder.WriteString("    at ")
 				colorer.ColoredText(s.Name, fmthelper.BrightCyan)
 				builder.WriteString(" (")
 				colorer.ColoredText(s.File, fmthelper.BrightGreen)
@@ -227,10 +273,14 @@ func formatBruhStacked(b []byte, unpacker *Unpacker, colored, sourced, typed boo
 				builder.WriteByte(')')
 			}
 		}
+		writeSuppressed(builder, unpacker, upkElm.Suppressed, self, indent+"    ", "suppressed: ")
 		if i < lastIndex {
 			builder.WriteString("\n")
 		}
 	}
+	if gr := unpacker.Goroutine(); gr != nil {
+		writeBruhCreatedBy(gr, builder, colorer)
+	}
 	return builder.Bytes()
 }
 
@@ -276,7 +326,7 @@ func formatBruhSourced(b []byte, unpacker *Unpacker, colored, sourced bool) []by
 		if sourced && errSourceLines == nil {
 			builder.WriteByte('\n')
 			for i, s := range stack {
-				formatSingleStackWithSourceCode(s, sourceLines[i], builder, colorer)
+				formatSingleStackWithSourceCode(s, sourceLines[i], "", builder, colorer)
 			}
 		} else {
 			for _, s := range stack {
@@ -290,17 +340,52 @@ func formatBruhSourced(b []byte, unpacker *Unpacker, colored, sourced bool) []by
 			}
 		}
 	}
+	if gr := unpacker.Goroutine(); gr != nil {
+		writeBruhCreatedBy(gr, builder, colorer)
+	}
+	if suppressed := Suppressed(unpacker.Error()); len(suppressed) > 0 {
+		self := func(b []byte, unpacker *Unpacker) []byte {
+			return formatBruhSourced(b, unpacker, colored, sourced)
+		}
+		writeSuppressed(builder, unpacker, suppressed, self, "", "suppressed: ")
+	}
 
 	return builder.Bytes()
 }
 
+// writeBruhCreatedBy writes the stack of the goroutine that created the
+// goroutine gr.
+func writeBruhCreatedBy(gr *Goroutine, builder *fmthelper.StringBuilder, colorer fmthelper.Colorer) {
+	stack := gr.creatorStack()
+	if len(stack) == 0 {
+		return
+	}
+	builder.WriteString("\ncreated by")
+	if gr.CreatorID > 0 {
+		builder.WriteString(" goroutine ")
+		builder.WriteInt(int64(gr.CreatorID))
+	}
+	for _, s := range stack {
+		builder.WriteString("\n    at ")
+		colorer.ColoredText(s.Name, fmthelper.BrightCyan)
+		builder.WriteString(" (")
+		colorer.ColoredText(s.File, fmthelper.BrightGreen)
+		builder.WriteByte(':')
+		builder.WriteInt(int64(s.Line))
+		builder.WriteByte(')')
+	}
+}
+
 func formatSingleStackWithSourceCode(
 	s StackFrame,
 	sourceLines SourceLines,
+	indent string,
 	builder *fmthelper.StringBuilder,
 	colorer fmthelper.Colorer,
 ) {
-	builder.WriteString("\nat ")
+	builder.WriteByte('\n')
+	builder.WriteString(indent)
+	builder.WriteString("at ")
 	colorer.ColoredText(s.Name, fmthelper.BrightCyan)
 	builder.WriteString(" (")
 	colorer.ColoredText(s.File, fmthelper.BrightGreen)
@@ -318,12 +403,14 @@ func formatSingleStackWithSourceCode(
 	for k := range 5 {
 		lineNum := sourceLines[k].LineNum
 		if sourceLines[k].LineNum >= 0 {
+			builder.WriteByte('\n')
+			builder.WriteString(indent)
 			if k == 2 {
-				builder.WriteString("\n  ")
+				builder.WriteString("  ")
 				colorer.ColoredText("→", fmthelper.BrightRed)
 				builder.WriteByte(' ')
 			} else {
-				builder.WriteString("\n    ")
+				builder.WriteString("    ")
 			}
 			for range numDigits - fmthelper.DigitsInNumber(lineNum) {
 				builder.WriteByte(' ')
@@ -345,3 +432,28 @@ func formatSingleStackWithSourceCode(
 		}
 	}
 }
+
+// branchIndent returns the indentation for the element at index i, which
+// depends on how deep the element is nested in branches of an error tree.
+func branchIndent(upkErr UnpackedError, i int) string {
+	level := upkErr.BranchLevel(i)
+	if level == 0 {
+		return ""
+	}
+	return strings.Repeat("    ", level)
+}
+
+// branchIndex returns the position of the element at index i among the
+// children of its parent.
+func branchIndex(upkErr UnpackedError, i int) int {
+	p := upkErr[i].Parent
+	if p < 0 {
+		return 0
+	}
+	for j, c := range upkErr[p].Children {
+		if c == i {
+			return j
+		}
+	}
+	return 0
+}
@@ -19,6 +19,7 @@ func TestFormatBruhStacked(t *testing.T) {
 	wrappedExternalInterleavedError := wrappedExternalInterleavedError()
 	externallyWrappedNilError := externallyWrappedNilError()
 	wrappedGlobalError := wrappedGlobalError()
+	joinedError := joinedError()
 
 	assertBruhStacked := func(name string, err error, exp string) {
 		t.Run(name, func(t *testing.T) {
@@ -90,4 +91,17 @@ external error: %!w(<nil>)`)
     at testing.tRunner (/testing/testing.go:1234)
 globally wrapped
 root error`)
+	assertBruhStacked("Joined", joinedError, `joined
+    at github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError (/pkg/bruh/format_test.go:367)
+    at github.com/aisbergg/go-bruh/pkg/bruh_test.TestFormatBruhStacked (/pkg/bruh/format_bruh_trace_stacked_test.go:22)
+    at testing.tRunner (/testing/testing.go:1234)
+<no message>
+    at github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError (/pkg/bruh/format_test.go:367)
+    #0: wrapped 1
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1 (/pkg/bruh/format_test.go:34)
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError (/pkg/bruh/format_test.go:367)
+    root error
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.singleRootError (/pkg/bruh/format_test.go:23)
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1 (/pkg/bruh/format_test.go:33)
+    #1: external error`)
 }
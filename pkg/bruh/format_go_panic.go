@@ -17,6 +17,18 @@ import (
 //		file2:line2 +0x123456
 //	functionN
 //		fileN:lineN +0x123456
+//	created by function4 in goroutine 1
+//		file4:line4 +0x123456
+//	[originating from goroutine 1]:
+//	function4()
+//		file4:line4 +0x123456
+//	function5()
+//		file5:line5 +0x123456
+//
+// The `created by` section is only included for errors of goroutines started
+// with [Go] or parsed by [ParsePanic]. It contains the stack of the creating
+// goroutine, if known, in the same format as Go's runtime prints it with
+// GODEBUG=tracebackancestors=N.
 func GoPanicFormatter(b []byte, unpacker *Unpacker) []byte {
 	if unpacker.Error() == nil {
 		return b
@@ -37,18 +49,213 @@ func GoPanicFormatter(b []byte, unpacker *Unpacker) []byte {
 		if builder.Len() > 0 {
 			builder.WriteString("\n\n")
 		}
-		for i, s := range stack {
-			builder.WriteString(s.Name)
-			builder.WriteString("()\n\t")
-			builder.WriteString(s.File)
-			builder.WriteByte(':')
-			builder.WriteInt(int64(s.Line))
-			builder.WriteString(" +0x")
-			builder.WriteUintAsHex(uint64(s.ProgramCounter2))
+		for i := range stack {
+			builder.WriteString(stack[i].Name)
+			builder.WriteString("()")
+			writeGoPanicLocation(builder, &stack[i])
 			if i < len(stack)-1 {
 				builder.WriteByte('\n')
 			}
 		}
 	}
+	if gr := unpacker.Goroutine(); gr != nil && gr.CreatedBy != nil {
+		builder.WriteString("\ncreated by ")
+		builder.WriteString(gr.CreatedBy.Name)
+		if gr.CreatorID > 0 {
+			builder.WriteString(" in goroutine ")
+			builder.WriteInt(int64(gr.CreatorID))
+		}
+		writeGoPanicLocation(builder, gr.CreatedBy)
+		if len(gr.Creator) > 0 {
+			builder.WriteString("\n[originating from goroutine ")
+			builder.WriteInt(int64(gr.CreatorID))
+			builder.WriteString("]:")
+			for i := range gr.Creator {
+				builder.WriteByte('\n')
+				builder.WriteString(gr.Creator[i].Name)
+				builder.WriteString("()")
+				writeGoPanicLocation(builder, &gr.Creator[i])
+			}
+		}
+	}
+	return builder.Bytes()
+}
+
+// writeGoPanicLocation writes the location line of a frame for the
+// [GoPanicFormatter].
+func writeGoPanicLocation(builder *fmthelper.StringBuilder, s *StackFrame) {
+	builder.WriteString("\n\t")
+	builder.WriteString(s.File)
+	builder.WriteByte(':')
+	builder.WriteInt(int64(s.Line))
+	builder.WriteString(" +0x")
+	if s.ProgramCounter2 != 0 {
+		builder.WriteUintAsHex(uint64(s.ProgramCounter2))
+	} else {
+		builder.WriteUintAsHex(uint64(s.Offset))
+	}
+}
+
+// GoRuntimePanicFormatter is an error formatter that produces error traces in
+// the exact format of Go's runtime panics, so that they can be processed by
+// tools that parse panics, like panicparse or [ParsePanic]. Unlike
+// [GoPanicFormatter], the `+0x` offsets are relative to the function entry and
+// the output includes the goroutine header and the `created by` section. Like
+// the runtime, inlined calls, which have no `+0x` offset, are written with
+// `(...)`. The arguments of the other calls are not known and are left out,
+// therefore they are written with `()`.
+//
+// If the chain contains errors created from recovered panics, see [PanicErr],
+// the header states the panic values instead of the messages of the chain.
+// Panics that were recovered and then caused another panic are written the
+// same way as by the runtime:
+//
+//	panic: first [recovered]
+//		panic: second
+//
+// A value that is panicked again with [Repanic] and then recovered results in a
+// single [PanicErr], therefore it is written once and without the
+// `[recovered, repanicked]` mark of Go 1.25 and later.
+//
+// The goroutine is taken from the deepest error in the chain that knows it,
+// e.g. an error of a [Panic] parsed by [ParsePanic]. Otherwise, the goroutine
+// header states goroutine 1 in the running state. For errors of goroutines
+// started with [Go], the stack of the creating goroutine is included the same
+// way as the runtime prints it with GODEBUG=tracebackancestors=N.
+//
+// # Output Format
+//
+//	panic: errorMsg1: errorMsg2: errorMsgN
+//
+//	goroutine 1 [running]:
+//	function1()
+//		file1:line1 +0x1d
+//	inlinedFunction(...)
+//		file2:line2
+//	function2()
+//		file2:line2 +0x25
+//	created by function3 in goroutine 1
+//		file3:line3 +0x4f
+//	[originating from goroutine 1]:
+//	function3()
+//		file3:line3 +0x4f
+//	function4()
+//		file4:line4 +0x18
+func GoRuntimePanicFormatter(b []byte, unpacker *Unpacker) []byte {
+	err := unpacker.Error()
+	if err == nil {
+		return b
+	}
+	stack := unpacker.CombinedStack()
+	// allocate a large buffer to avoid later reallocations
+	// message: 80 per error
+	// location: 160 per location
+	builder := fmthelper.New(b)
+	builder.Grow(unpacker.ChainLen()*80 + (len(stack)+1)*160)
+
+	// find the goroutine and the kind of the crash
+	gr := unpacker.Goroutine()
+	prefix := "panic: "
+	var panics []*PanicErr
+	for uerr := err; uerr != nil; uerr = unwrapFirst(uerr) {
+		if perr, ok := uerr.(*PanicErr); ok {
+			panics = append(panics, perr)
+		}
+		if typeName(uerr) == "fatal error" {
+			prefix = "fatal error: "
+		}
+	}
+
+	builder.WriteString(prefix)
+	if len(panics) > 0 {
+		writeGoRuntimePanics(builder, panics)
+	} else {
+		builder.WriteString(Message(err))
+	}
+	builder.WriteString("\n\ngoroutine ")
+	if gr != nil && gr.ID > 0 {
+		builder.WriteInt(int64(gr.ID))
+	} else {
+		builder.WriteByte('1')
+	}
+	builder.WriteString(" [")
+	if gr != nil && gr.State != "" {
+		builder.WriteString(gr.State)
+	} else {
+		builder.WriteString("running")
+	}
+	builder.WriteString("]:")
+	for i := range stack {
+		writeGoRuntimeFrame(builder, &stack[i])
+	}
+	if gr != nil && gr.CreatedBy != nil {
+		builder.WriteString("\ncreated by ")
+		builder.WriteString(gr.CreatedBy.Name)
+		if gr.CreatorID > 0 {
+			builder.WriteString(" in goroutine ")
+			builder.WriteInt(int64(gr.CreatorID))
+		}
+		writeGoRuntimeLocation(builder, gr.CreatedBy)
+	}
+	if gr != nil && len(gr.Creator) > 0 {
+		builder.WriteString("\n[originating from goroutine ")
+		builder.WriteInt(int64(gr.CreatorID))
+		builder.WriteString("]:")
+		for i := range gr.Creator {
+			writeGoRuntimeFrame(builder, &gr.Creator[i])
+		}
+	}
 	return builder.Bytes()
 }
+
+// writeGoRuntimePanics writes the values of the given panics, which are ordered
+// from the outermost to the innermost error of the chain, in the order they
+// occurred, the same way as Go's runtime does.
+func writeGoRuntimePanics(builder *fmthelper.StringBuilder, panics []*PanicErr) {
+	for i := len(panics) - 1; i >= 0; i-- {
+		if i < len(panics)-1 {
+			builder.WriteString("\n\tpanic: ")
+		}
+		builder.WriteString(panicValueMessage(panics[i]))
+		if i > 0 {
+			builder.WriteString(" [recovered]")
+		}
+	}
+}
+
+// panicValueMessage returns the message of the panic value, as it is printed
+// by Go's runtime.
+func panicValueMessage(e *PanicErr) string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return e.msg
+}
+
+// writeGoRuntimeFrame writes a frame the same way as Go's runtime does. Since
+// the arguments are not known, they are left out. Inlined calls are written
+// with `(...)`, like the runtime does.
+func writeGoRuntimeFrame(builder *fmthelper.StringBuilder, s *StackFrame) {
+	builder.WriteByte('\n')
+	builder.WriteString(s.Name)
+	// inlined calls have no offset, see [StackFrame.Offset]
+	if s.Offset == 0 {
+		builder.WriteString("(...)")
+	} else {
+		builder.WriteString("()")
+	}
+	writeGoRuntimeLocation(builder, s)
+}
+
+// writeGoRuntimeLocation writes the location line of a frame the same way as
+// Go's runtime does.
+func writeGoRuntimeLocation(builder *fmthelper.StringBuilder, s *StackFrame) {
+	builder.WriteString("\n\t")
+	builder.WriteString(s.File)
+	builder.WriteByte(':')
+	builder.WriteInt(int64(s.Line))
+	if s.Offset > 0 {
+		builder.WriteString(" +0x")
+		builder.WriteUintAsHex(uint64(s.Offset))
+	}
+}
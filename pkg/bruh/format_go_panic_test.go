@@ -141,6 +141,108 @@ testing.tRunner()
 	/testing/testing.go:1234 +0x012345`)
 }
 
+func TestFormatGoRuntimePanic(t *testing.T) {
+	t.Parallel()
+
+	singleRootError := singleRootError()
+	wrappedError := wrappedError3()
+	externalError := externalError()
+	wrappedExternalError := wrappedExternalError()
+
+	assertGoRuntimePanic := func(name string, err error, exp string) {
+		t.Run(name, func(t *testing.T) {
+			result := goPanicReplacePath(bruh.StringFormat(err, bruh.GoRuntimePanicFormatter))
+			if result != exp {
+				t.Errorf("expected:\n|%s|\n\ngot:\n|%s|", exp, result)
+			}
+		})
+	}
+
+	assertGoRuntimePanic("Nil", nil, "")
+	assertGoRuntimePanic("SingleRoot", singleRootError, `panic: root error
+
+goroutine 1 [running]:
+github.com/aisbergg/go-bruh/pkg/bruh_test.singleRootError()
+	/pkg/bruh/format_test.go:23 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.TestFormatGoRuntimePanic()
+	/pkg/bruh/format_go_panic_test.go:147 +0x012345
+testing.tRunner()
+	/testing/testing.go:1234 +0x012345`)
+	assertGoRuntimePanic("Wrapped", wrappedError, `panic: wrapped 3: wrapped 2: wrapped 1: root error
+
+goroutine 1 [running]:
+github.com/aisbergg/go-bruh/pkg/bruh_test.singleRootError()
+	/pkg/bruh/format_test.go:23 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1()
+	/pkg/bruh/format_test.go:33 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1()
+	/pkg/bruh/format_test.go:34 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError2()
+	/pkg/bruh/format_test.go:41 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError2()
+	/pkg/bruh/format_test.go:42 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError3()
+	/pkg/bruh/format_test.go:49 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError3()
+	/pkg/bruh/format_test.go:50 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.TestFormatGoRuntimePanic()
+	/pkg/bruh/format_go_panic_test.go:148 +0x012345
+testing.tRunner()
+	/testing/testing.go:1234 +0x012345`)
+	assertGoRuntimePanic("External", externalError, `panic: external error
+
+goroutine 1 [running]:`)
+	assertGoRuntimePanic("WrappedExternal", wrappedExternalError, `panic: wrapped 1: external error
+
+goroutine 1 [running]:
+github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedExternalError()
+	/pkg/bruh/format_test.go:76 +0x012345
+github.com/aisbergg/go-bruh/pkg/bruh_test.TestFormatGoRuntimePanic()
+	/pkg/bruh/format_go_panic_test.go:150 +0x012345
+testing.tRunner()
+	/testing/testing.go:1234 +0x012345`)
+
+	// parsed panics are rendered exactly as the runtime did
+	t.Run("ParsedPanic", func(t *testing.T) {
+		output := `panic: something went wrong [recovered]
+	panic: something went wrong again
+
+goroutine 18 [running]:
+main.(*worker).do()
+	/app/worker.go:42 +0x1d
+main.run.func1(...)
+	/app/inline.go:17
+created by main.run in goroutine 1
+	/app/main.go:15 +0x4f`
+		p, err := bruh.ParsePanic(strings.NewReader(output))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := bruh.StringFormat(p.Err(), bruh.GoRuntimePanicFormatter)
+		if result != output {
+			t.Errorf("expected:\n|%s|\n\ngot:\n|%s|", output, result)
+		}
+	})
+
+	// the output can be parsed again
+	t.Run("Reparse", func(t *testing.T) {
+		p, err := bruh.ParsePanic(strings.NewReader(bruh.StringFormat(wrappedError, bruh.GoRuntimePanicFormatter)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expStack := bruh.NewReport(wrappedError).Err().(*bruh.RemoteErr).Stack()
+		if len(p.Goroutines) != 1 || len(p.Goroutines[0].Stack) != len(expStack) {
+			t.Fatalf("unexpected goroutines: %+v", p.Goroutines)
+		}
+		for i, frame := range p.Goroutines[0].Stack {
+			exp := expStack[i]
+			if frame.Name != exp.Name || frame.File != exp.File || frame.Line != exp.Line || frame.Offset != exp.Offset {
+				t.Errorf("frame %d: expected %+v, got %+v", i, exp, frame)
+			}
+		}
+	})
+}
+
 var goPanicRegexpTestingGo = regexp.MustCompile(`testing\.go:\d+`)
 
 func goPanicReplacePath(s string) string {
@@ -0,0 +1,299 @@
+package bruh
+
+import (
+	"go/token"
+	"strings"
+
+	"github.com/aisbergg/go-bruh/pkg/bruh/fmthelper"
+)
+
+// HTMLFormatterOptions configures the formatter returned by [NewHTMLFormatter].
+type HTMLFormatterOptions struct {
+	// Fragment produces an HTML fragment instead of a complete document. The
+	// fragment is a single `<div>` element that carries its own styles, so it
+	// can be embedded into existing pages.
+	Fragment bool
+	// Title is the title of the document. Defaults to the message of the error
+	// chain. It is not used for fragments.
+	Title string
+	// Source adds syntax-highlighted snippets of the source code to the stack
+	// frames, if the source code is available at runtime.
+	Source bool
+	// SourceContext is the number of source lines before and after the line
+	// of a stack frame that are shown, if Source is enabled.
+	SourceContext int
+	// PlainFormatter produces the plain trace that can be copied to the
+	// clipboard. Defaults to [BruhFormatter].
+	PlainFormatter Formatter
+}
+
+// HTMLFormatter is an error formatter that produces a self-contained HTML
+// document for developer error pages, e.g. of admin UIs in development mode.
+// Every error of the chain is shown in a collapsible section with its stack
+// and syntax-highlighted snippets of the source code. A plain trace, produced
+// by [BruhFormatter], can be copied to the clipboard. Use [NewHTMLFormatter] to
+// produce fragments or to change what is included.
+//
+// All text, including messages, type names and source code, is escaped, so
+// that error messages can't inject HTML.
+//
+// The output must not be shown to the users of production systems, because it
+// reveals the source code and the internals of the application.
+func HTMLFormatter(b []byte, unpacker *Unpacker) []byte {
+	return formatHTML(b, unpacker, &HTMLFormatterOptions{Source: true, SourceContext: 2, PlainFormatter: BruhFormatter})
+}
+
+// NewHTMLFormatter returns a [Formatter] that produces HTML documents or
+// fragments with the given options. See [HTMLFormatter] for details.
+//
+// Example usage:
+//
+//	f := bruh.NewHTMLFormatter(bruh.HTMLFormatterOptions{Fragment: true, Source: true, SourceContext: 3})
+//	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+//	w.Write(bruh.AppendStringFormat(nil, err, f))
+func NewHTMLFormatter(opts HTMLFormatterOptions) Formatter {
+	if opts.PlainFormatter == nil {
+		opts.PlainFormatter = BruhFormatter
+	}
+	return func(b []byte, unpacker *Unpacker) []byte {
+		return formatHTML(b, unpacker, &opts)
+	}
+}
+
+// htmlStyle is the style sheet of the HTML output. All classes are prefixed to
+// avoid clashes with the styles of the embedding page.
+const htmlStyle = `.bruh-error{font-family:system-ui,sans-serif;color:#1f2328;background:#fff;padding:1rem}` +
+	`.bruh-error h1{font-size:1.25rem;color:#cf222e;margin:0 0 1rem;white-space:pre-wrap}` +
+	`.bruh-error details{border:1px solid #d0d7de;border-radius:6px;margin:.5rem 0;padding:.25rem .75rem}` +
+	`.bruh-error summary{cursor:pointer;font-weight:600;white-space:pre-wrap}` +
+	`.bruh-error ol{margin:.5rem 0;padding-left:1.5rem}` +
+	`.bruh-error li{margin:.25rem 0}` +
+	`.bruh-error pre{background:#f6f8fa;border-radius:6px;padding:.5rem;margin:.25rem 0;overflow-x:auto}` +
+	`.bruh-type{color:#8250df}.bruh-kind{color:#953800}` +
+	`.bruh-func{font-family:monospace;color:#0550ae}.bruh-file{font-family:monospace;color:#116329}` +
+	`.bruh-line{display:block;min-height:1.2em}.bruh-current{background:#ffebe9;font-weight:600}` +
+	`.bruh-lineno{display:inline-block;min-width:3em;color:#6e7781;user-select:none}` +
+	`.bruh-kw{color:#cf222e}.bruh-str{color:#0a3069}.bruh-num{color:#0550ae}.bruh-com{color:#6e7781}` +
+	`.bruh-label{font-weight:600;color:#6e7781;margin-top:.5rem}` +
+	`.bruh-copy{float:right;cursor:pointer}`
+
+// htmlCopyScript copies the plain trace, which is the text of the element
+// following the button, to the clipboard.
+const htmlCopyScript = `navigator.clipboard.writeText(this.nextElementSibling.textContent)`
+
+func formatHTML(b []byte, unpacker *Unpacker, opts *HTMLFormatterOptions) []byte {
+	if unpacker.Error() == nil {
+		return b
+	}
+	// allocate a large buffer to avoid later reallocations
+	// style and fixed text: 2000
+	// message and type: 300 per error
+	// location: 300 per location
+	// source lines: 150 per line
+	builder := fmthelper.New(b)
+	guessCap := 2000 + unpacker.ChainLen()*300
+	for _, upkElm := range unpacker.Unpack() {
+		guessCap += len(upkElm.PartialStack) * 300
+		if opts.Source {
+			guessCap += len(upkElm.PartialStack) * (2*opts.SourceContext + 1) * 150
+		}
+	}
+	builder.Grow(guessCap)
+
+	msg := Message(unpacker.Error())
+	if !opts.Fragment {
+		title := opts.Title
+		if title == "" {
+			title = msg
+		}
+		builder.WriteString(`<!DOCTYPE html><html lang="en"><head><meta charset="utf-8"><title>`)
+		builder.WriteHTMLString(title)
+		builder.WriteString(`</title></head><body>`)
+	}
+	builder.WriteString("<div class=\"bruh-error\"><style>")
+	builder.WriteString(htmlStyle)
+	builder.WriteString("</style>\n<h1>")
+	builder.WriteHTMLString(msg)
+	builder.WriteString("</h1>\n")
+	writeHTMLErrors(builder, unpacker, opts)
+	builder.WriteString("<details class=\"bruh-plain\"><summary>Plain trace</summary>")
+	builder.WriteString("<button type=\"button\" class=\"bruh-copy\" onclick=\"")
+	builder.WriteString(htmlCopyScript)
+	builder.WriteString("\">Copy</button><pre>")
+	builder.WriteHTMLString(StringFormat(unpacker.Error(), opts.PlainFormatter, unpacker.unpackAll))
+	builder.WriteString("</pre></details>\n</div>")
+	if !opts.Fragment {
+		builder.WriteString("</body></html>")
+	}
+	return builder.Bytes()
+}
+
+// writeHTMLErrors writes a collapsible section for each error of the chain.
+func writeHTMLErrors(builder *fmthelper.StringBuilder, unpacker *Unpacker, opts *HTMLFormatterOptions) {
+	upkErr := unpacker.Unpack()
+	var sourceLines [][]SourceLines
+	if opts.Source {
+		sourceLines, _ = unpacker.GetSourceLines(opts.SourceContext, 120, true)
+	}
+
+	for i := range upkErr {
+		upkElm := &upkErr[i]
+		builder.WriteString("<details open")
+		if level := upkErr.BranchLevel(i); level > 0 {
+			builder.WriteString(" style=\"margin-left:")
+			builder.WriteInt(int64(level * 2))
+			builder.WriteString("rem\"")
+		}
+		builder.WriteString("><summary><span class=\"bruh-type\">")
+		builder.WriteHTMLString(typeName(upkElm.Err))
+		builder.WriteString("</span>")
+		if kind := elementKind(upkElm.Err); kind != KindUnknown {
+			builder.WriteString(" <span class=\"bruh-kind\">[")
+			builder.WriteHTMLString(string(kind))
+			builder.WriteString("]</span>")
+		}
+		builder.WriteString(": ")
+		if upkElm.Msg != "" {
+			builder.WriteHTMLString(upkElm.Msg)
+		} else {
+			builder.WriteString("&lt;no message&gt;")
+		}
+		builder.WriteString("</summary>\n")
+		var elmSourceLines []SourceLines
+		if sourceLines != nil {
+			elmSourceLines = sourceLines[i]
+		}
+		writeHTMLStack(builder, upkElm.PartialStack, elmSourceLines, opts.SourceContext)
+		for _, serr := range upkElm.Suppressed {
+			builder.WriteString("<div class=\"bruh-label\">Suppressed:</div>\n")
+			sunpacker := newUnpacker(serr, unpacker.unpackAll)
+			writeHTMLErrors(builder, sunpacker, opts)
+			disposeUnpacker(sunpacker)
+		}
+		builder.WriteString("</details>\n")
+	}
+
+	if gr := unpacker.Goroutine(); gr != nil {
+		if stack := gr.creatorStack(); len(stack) > 0 {
+			builder.WriteString("<details open><summary>Created by goroutine")
+			if gr.CreatorID > 0 {
+				builder.WriteByte(' ')
+				builder.WriteInt(int64(gr.CreatorID))
+			}
+			builder.WriteString("</summary>\n")
+			writeHTMLStack(builder, stack, nil, 0)
+			builder.WriteString("</details>\n")
+		}
+	}
+}
+
+// writeHTMLStack writes the stack frames as an ordered list. sourceLines are
+// the source lines of the frames, or nil if they are not available.
+func writeHTMLStack(builder *fmthelper.StringBuilder, stack Stack, sourceLines []SourceLines, ctxLines int) {
+	if len(stack) == 0 {
+		return
+	}
+	builder.WriteString("<ol>\n")
+	for j, s := range stack {
+		builder.WriteString("<li><span class=\"bruh-func\">")
+		builder.WriteHTMLString(s.Name)
+		builder.WriteString("</span> <span class=\"bruh-file\">")
+		builder.WriteHTMLString(s.File)
+		builder.WriteByte(':')
+		builder.WriteInt(int64(s.Line))
+		builder.WriteString("</span>")
+		if sourceLines != nil {
+			builder.WriteString("<pre><code>")
+			for k, l := range sourceLines[j] {
+				// lines outside of the file are marked by a negative number
+				if l.LineNum < 0 {
+					continue
+				}
+				if k == ctxLines {
+					builder.WriteString("<span class=\"bruh-line bruh-current\">")
+				} else {
+					builder.WriteString("<span class=\"bruh-line\">")
+				}
+				builder.WriteString("<span class=\"bruh-lineno\">")
+				builder.WriteInt(int64(l.LineNum))
+				builder.WriteString("</span>")
+				writeHighlightedGo(builder, strings.ReplaceAll(l.Source, "\t", "    "))
+				builder.WriteString("</span>")
+			}
+			builder.WriteString("</code></pre>")
+		}
+		builder.WriteString("</li>\n")
+	}
+	builder.WriteString("</ol>\n")
+}
+
+// writeHighlightedGo writes a single line of Go source code with keywords,
+// literals and comments wrapped in spans for syntax highlighting. Since the
+// line is looked at in isolation, comments and raw strings spanning multiple
+// lines are only highlighted on the line they start.
+func writeHighlightedGo(builder *fmthelper.StringBuilder, line string) {
+	span := func(class, text string) {
+		builder.WriteString("<span class=\"")
+		builder.WriteString(class)
+		builder.WriteString("\">")
+		builder.WriteHTMLString(text)
+		builder.WriteString("</span>")
+	}
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "//"):
+			span("bruh-com", line[i:])
+			return
+		case strings.HasPrefix(line[i:], "/*"):
+			end := strings.Index(line[i+2:], "*/")
+			if end < 0 {
+				span("bruh-com", line[i:])
+				return
+			}
+			end += i + 4
+			span("bruh-com", line[i:end])
+			i = end
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(line) && line[end] != c {
+				if line[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(line))
+			span("bruh-str", line[i:end])
+			i = end
+		case isIdentStart(c):
+			end := i + 1
+			for end < len(line) && (isIdentStart(line[end]) || isDigit(line[end])) {
+				end++
+			}
+			if word := line[i:end]; token.IsKeyword(word) {
+				span("bruh-kw", word)
+			} else {
+				builder.WriteHTMLString(word)
+			}
+			i = end
+		case isDigit(c):
+			end := i + 1
+			for end < len(line) && (isDigit(line[end]) || isIdentStart(line[end]) || line[end] == '.') {
+				end++
+			}
+			span("bruh-num", line[i:end])
+			i = end
+		default:
+			builder.WriteHTMLString(line[i : i+1])
+			i++
+		}
+	}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
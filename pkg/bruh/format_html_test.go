@@ -0,0 +1,79 @@
+package bruh_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"html"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+func TestHTMLFormatter(t *testing.T) {
+	t.Parallel()
+	err := bruh.NewKind(bruh.KindNotFound, "user <script>alert('x')</script> not found")
+	bruh.AddSuppressed(&err, bruh.New("cleanup & close"))
+	err = bruh.Wrap(bruh.Join(err, errors.New("external")), "handling \"request\"")
+
+	// assertWellFormed checks that the output is well-formed markup, which
+	// would be broken by unescaped text
+	assertWellFormed := func(t *testing.T, out string) {
+		t.Helper()
+		out = strings.TrimPrefix(out, "<!DOCTYPE html>")
+		out = strings.ReplaceAll(out, `<meta charset="utf-8">`, "")
+		out = strings.ReplaceAll(out, "<details open", `<details open=""`)
+		dec := xml.NewDecoder(strings.NewReader(out))
+		dec.Entity = map[string]string{"lt": "<", "gt": ">", "amp": "&"}
+		for {
+			_, err := dec.Token()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				t.Fatalf("malformed output: %v\n%s", err, out)
+			}
+		}
+	}
+
+	t.Run("Document", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		out := bruh.StringFormat(err, bruh.HTMLFormatter)
+		assertWellFormed(t, out)
+		assert.True(strings.HasPrefix(out, "<!DOCTYPE html><html lang=\"en\"><head><meta charset=\"utf-8\"><title>handling &#34;request&#34;: "))
+		assert.True(strings.HasSuffix(out, "</div></body></html>"))
+		assert.False(strings.Contains(out, "<script>"), "messages must be escaped")
+		assert.True(strings.Contains(out, "user &lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt; not found"))
+		assert.Equal(5, strings.Count(out, "<details open"), "expected a section per error, including the suppressed one")
+		assert.True(strings.Contains(out, "<span class=\"bruh-kind\">[NotFound]</span>"))
+		assert.True(strings.Contains(out, "<div class=\"bruh-label\">Suppressed:</div>"))
+		assert.True(strings.Contains(out, "cleanup &amp; close"))
+
+		// source lines
+		assert.True(strings.Contains(out, "<span class=\"bruh-line bruh-current\">"))
+		assert.True(strings.Contains(out, "<span class=\"bruh-kw\">func</span>"))
+
+		// plain trace
+		plain := html.EscapeString(bruh.StringFormat(err, bruh.BruhFormatter))
+		assert.True(strings.Contains(out, "Copy</button><pre>"+plain+"</pre>"))
+	})
+
+	t.Run("Fragment", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		f := bruh.NewHTMLFormatter(bruh.HTMLFormatterOptions{Fragment: true, PlainFormatter: bruh.JavaStackTraceFormatter})
+		out := bruh.StringFormat(err, f)
+		assertWellFormed(t, out)
+		assert.True(strings.HasPrefix(out, "<div class=\"bruh-error\"><style>"))
+		assert.True(strings.HasSuffix(out, "</div>"))
+		assert.False(strings.Contains(out, "<span class=\"bruh-line"), "expected no source lines")
+		plain := html.EscapeString(bruh.StringFormat(err, bruh.JavaStackTraceFormatter))
+		assert.True(strings.Contains(out, "<pre>"+plain+"</pre>"))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		assert.Equal("", bruh.StringFormat(nil, bruh.HTMLFormatter))
+	})
+}
@@ -21,6 +21,33 @@ import (
 //	    at <function1> (<file1>:<line1>)
 //	    at <function2> (<file2>:<line2>)
 //	    at <functionN> (<fileN>:<lineN>)
+//
+// Errors with a [Kind] are annotated with the kind in brackets after the type
+// name, e.g. `*bruh.Err [NotFound]: errorMsg1`.
+//
+// If an error wraps multiple errors (error tree), the causes of each branch are
+// indented below the wrapping error:
+//
+//	<typeName1>: <errorMsg1>
+//	    at <function1> (<file1>:<line1>)
+//	    Caused by: <typeName2>: <errorMsg2>
+//	        at <function2> (<file2>:<line2>)
+//	    Caused by: <typeName3>: <errorMsg3>
+//	        at <function3> (<file3>:<line3>)
+//
+// For errors of goroutines started with [Go] or parsed by [ParsePanic], the
+// stack of the creating goroutine is appended:
+//
+//	Created by: goroutine <id>
+//	    at <function4> (<file4>:<line4>)
+//
+// Suppressed errors, see [AddSuppressed], are indented below the stack of the
+// error that suppressed them:
+//
+//	<typeName1>: <errorMsg1>
+//	    at <function1> (<file1>:<line1>)
+//	    Suppressed: <typeName5>: <errorMsg5>
+//	        at <function5> (<file5>:<line5>)
 func JavaStackTraceFormatter(b []byte, unpacker *Unpacker) []byte {
 	if unpacker.Error() == nil {
 		return b
@@ -37,10 +64,12 @@ func JavaStackTraceFormatter(b []byte, unpacker *Unpacker) []byte {
 	builder.Grow(guessCap)
 
 	for i, upkElm := range upkErr {
+		indent := branchIndent(upkErr, i)
+		builder.WriteString(indent)
 		if i > 0 {
 			builder.WriteString("Caused by: ")
 		}
-		builder.WriteString(typeName(upkElm.Err))
+		builder.WriteString(typeNameWithKind(upkElm.Err))
 		builder.WriteString(": ")
 		if upkElm.Msg != "" {
 			builder.WriteString(upkElm.Msg)
@@ -49,6 +78,7 @@ func JavaStackTraceFormatter(b []byte, unpacker *Unpacker) []byte {
 		}
 		for _, s := range upkElm.PartialStack {
 			builder.WriteByte('\n')
+			builder.WriteString(indent)
 			builder.WriteString("    at ")
 			builder.WriteString(s.Name)
 			builder.WriteString(" (")
@@ -57,9 +87,28 @@ func JavaStackTraceFormatter(b []byte, unpacker *Unpacker) []byte {
 			builder.WriteInt(int64(s.Line))
 			builder.WriteByte(')')
 		}
+		writeSuppressed(builder, unpacker, upkElm.Suppressed, JavaStackTraceFormatter, indent+"    ", "Suppressed: ")
 		if i < len(upkErr)-1 {
 			builder.WriteByte('\n')
 		}
 	}
+	if gr := unpacker.Goroutine(); gr != nil {
+		if stack := gr.creatorStack(); len(stack) > 0 {
+			builder.WriteString("\nCreated by: goroutine")
+			if gr.CreatorID > 0 {
+				builder.WriteByte(' ')
+				builder.WriteInt(int64(gr.CreatorID))
+			}
+			for _, s := range stack {
+				builder.WriteString("\n    at ")
+				builder.WriteString(s.Name)
+				builder.WriteString(" (")
+				builder.WriteString(s.File)
+				builder.WriteByte(':')
+				builder.WriteInt(int64(s.Line))
+				builder.WriteByte(')')
+			}
+		}
+	}
 	return builder.Bytes()
 }
@@ -21,6 +21,7 @@ func TestFormatJavaStackTrace(t *testing.T) {
 	wrappedExternalInterleavedError := wrappedExternalInterleavedError()
 	externallyWrappedNilError := externallyWrappedNilError()
 	wrappedGlobalError := wrappedGlobalError()
+	joinedError := joinedError()
 
 	assertJavaStackTrace := func(name string, err error, exp string) {
 		t.Run(name, func(t *testing.T) {
@@ -102,6 +103,19 @@ Caused by: *fmt.wrapError: external error: %!w(<nil>)`)
     at testing.tRunner (/testing/testing.go:1234)
 Caused by: *bruh.Err: globally wrapped
 Caused by: *bruh.Err: root error`)
+	assertJavaStackTrace("Joined", joinedError, `*bruh.Err: joined
+    at github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError (/pkg/bruh/format_test.go:367)
+    at github.com/aisbergg/go-bruh/pkg/bruh_test.TestFormatJavaStackTrace (/pkg/bruh/format_java_stacktrace_test.go:24)
+    at testing.tRunner (/testing/testing.go:1234)
+Caused by: *bruh.joinError: _
+    at github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError (/pkg/bruh/format_test.go:367)
+    Caused by: *bruh.Err: wrapped 1
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1 (/pkg/bruh/format_test.go:34)
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError (/pkg/bruh/format_test.go:367)
+    Caused by: *bruh.Err: root error
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.singleRootError (/pkg/bruh/format_test.go:23)
+        at github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1 (/pkg/bruh/format_test.go:33)
+    Caused by: *errors.errorString: external error`)
 }
 
 var javaStackTraceRegexpTestingGo = regexp.MustCompile(`testing\.go:\d+`)
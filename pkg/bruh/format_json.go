@@ -0,0 +1,296 @@
+package bruh
+
+import (
+	"github.com/aisbergg/go-bruh/pkg/bruh/fmthelper"
+)
+
+// JSONSchemaVersion is the version of the schema of the documents produced by
+// [JSONFormatter]. It is incremented whenever the schema changes in an
+// incompatible way. Adding fields is not considered incompatible.
+const JSONSchemaVersion = 1
+
+// JSONFormatterOptions configures the formatter returned by [NewJSONFormatter].
+type JSONFormatterOptions struct {
+	// Pretty enables indented, multi-line output. By default, the document is
+	// written on a single line, as required by newline delimited JSON (NDJSON)
+	// log pipelines.
+	Pretty bool
+	// Indent is the indentation of a nesting level of the pretty output.
+	// Defaults to two spaces.
+	Indent string
+	// CombinedStack adds the combined stack of the error chain to the
+	// document, see [Unpacker.CombinedStack].
+	CombinedStack bool
+	// Source adds the source lines of the stack frames to the document, if the
+	// source code is available at runtime.
+	Source bool
+	// SourceContext is the number of source lines before and after the line
+	// of a stack frame that are added, if Source is enabled.
+	SourceContext int
+}
+
+// JSONFormatter is an error formatter that produces a single-line JSON
+// document, which is suitable for newline delimited JSON (NDJSON) log
+// pipelines. The document follows a versioned schema, see [JSONSchemaVersion].
+// Use [NewJSONFormatter] to produce pretty-printed documents, combined stacks
+// or source lines.
+//
+// # Schema
+//
+// Version 1 of the schema:
+//
+//	{
+//	  "version": 1,
+//	  "message": "<full message of the chain>",
+//	  "errors": [
+//	    {
+//	      "type": "<type name>",
+//	      "kind": "<kind, omitted if unknown>",
+//	      "message": "<message of this error only>",
+//	      "parent": <index of the wrapping error, -1 for the root>,
+//	      "children": [<indices of the wrapped errors, omitted if empty>],
+//	      "stack": [<partial stack of this error>],
+//	      "suppressed": [<documents without version, omitted if empty>]
+//	    }
+//	  ],
+//	  "created_by": {
+//	    "id": <id of the creating goroutine, omitted if unknown>,
+//	    "stack": [<stack of the creating goroutine>]
+//	  },
+//	  "stack": [<combined stack, omitted unless enabled>]
+//	}
+//
+// The errors are listed in depth-first order, the same way as they are
+// returned by [Unpacker.Unpack]. The partial stack of an error leaves out the
+// frames that are already part of the stack of the wrapping error. The
+// suppressed errors, see [AddSuppressed], are documents of their own. The
+// creating goroutine is only known for errors of goroutines started with [Go]
+// or parsed by [ParsePanic] and is omitted otherwise. A stack frame is
+// described by:
+//
+//	{
+//	  "function": "<function name>",
+//	  "file": "<file path>",
+//	  "line": <line number>,
+//	  "source": [{"line": <line number>, "code": "<source line>"}]
+//	}
+//
+// The source lines are omitted unless enabled.
+func JSONFormatter(b []byte, unpacker *Unpacker) []byte {
+	return formatJSON(b, unpacker, &JSONFormatterOptions{})
+}
+
+// NewJSONFormatter returns a [Formatter] that produces JSON documents with the
+// given options. See [JSONFormatter] for the schema of the documents.
+//
+// Example usage:
+//
+//	f := bruh.NewJSONFormatter(bruh.JSONFormatterOptions{Pretty: true, CombinedStack: true})
+//	fmt.Println(bruh.StringFormat(err, f))
+func NewJSONFormatter(opts JSONFormatterOptions) Formatter {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	return func(b []byte, unpacker *Unpacker) []byte {
+		return formatJSON(b, unpacker, &opts)
+	}
+}
+
+func formatJSON(b []byte, unpacker *Unpacker, opts *JSONFormatterOptions) []byte {
+	if unpacker.Error() == nil {
+		return b
+	}
+	// allocate a large buffer to avoid later reallocations
+	// message and type: 200 per error
+	// location: 200 per location
+	builder := fmthelper.New(b)
+	guessCap := unpacker.ChainLen() * 200
+	if opts.CombinedStack {
+		guessCap += len(unpacker.CombinedStack()) * 200
+	}
+	builder.Grow(guessCap)
+
+	w := jsonWriter{builder: builder, opts: opts}
+	w.writeDocument(unpacker, true)
+	return builder.Bytes()
+}
+
+// -----------------------------------------------------------------------------
+
+// jsonWriter writes the JSON documents of [JSONFormatter].
+type jsonWriter struct {
+	builder *fmthelper.StringBuilder
+	opts    *JSONFormatterOptions
+	// counts holds the number of values written to each open object or array.
+	counts []int
+}
+
+// writeDocument writes the document of the unpacked error.
+func (w *jsonWriter) writeDocument(unpacker *Unpacker, versioned bool) {
+	upkErr := unpacker.Unpack()
+	var sourceLines [][]SourceLines
+	if w.opts.Source {
+		sourceLines, _ = unpacker.GetSourceLines(w.opts.SourceContext, 120, false)
+	}
+
+	w.open('{')
+	if versioned {
+		w.key("version")
+		w.builder.WriteInt(JSONSchemaVersion)
+	}
+	w.key("message")
+	w.builder.WriteJSONString(Message(unpacker.Error()))
+	w.key("errors")
+	w.open('[')
+	for i := range upkErr {
+		upkElm := &upkErr[i]
+		w.next()
+		w.open('{')
+		w.key("type")
+		w.builder.WriteJSONString(typeName(upkElm.Err))
+		if kind := elementKind(upkElm.Err); kind != KindUnknown {
+			w.key("kind")
+			w.builder.WriteJSONString(string(kind))
+		}
+		w.key("message")
+		w.builder.WriteJSONString(upkElm.Msg)
+		w.key("parent")
+		w.builder.WriteInt(int64(upkElm.Parent))
+		if len(upkElm.Children) > 0 {
+			w.key("children")
+			w.open('[')
+			for _, c := range upkElm.Children {
+				w.next()
+				w.builder.WriteInt(int64(c))
+			}
+			w.close(']')
+		}
+		w.key("stack")
+		var elmSourceLines []SourceLines
+		if sourceLines != nil {
+			elmSourceLines = sourceLines[i]
+		}
+		w.writeStack(upkElm.PartialStack, elmSourceLines)
+		if len(upkElm.Suppressed) > 0 {
+			w.key("suppressed")
+			w.open('[')
+			for _, serr := range upkElm.Suppressed {
+				w.next()
+				sunpacker := newUnpacker(serr, unpacker.unpackAll)
+				w.writeDocument(sunpacker, false)
+				disposeUnpacker(sunpacker)
+			}
+			w.close(']')
+		}
+		w.close('}')
+	}
+	w.close(']')
+
+	if gr := unpacker.Goroutine(); gr != nil {
+		if stack := gr.creatorStack(); len(stack) > 0 {
+			w.key("created_by")
+			w.open('{')
+			if gr.CreatorID > 0 {
+				w.key("id")
+				w.builder.WriteInt(int64(gr.CreatorID))
+			}
+			w.key("stack")
+			w.writeStack(stack, nil)
+			w.close('}')
+		}
+	}
+
+	if w.opts.CombinedStack {
+		stack := unpacker.CombinedStack()
+		var stackSourceLines []SourceLines
+		if w.opts.Source {
+			stackSourceLines, _ = stack.GetSourceLines(w.opts.SourceContext, 120, false)
+		}
+		w.key("stack")
+		w.writeStack(stack, stackSourceLines)
+	}
+	w.close('}')
+}
+
+// writeStack writes the stack frames. sourceLines are the source lines of the
+// frames, or nil if they are not available.
+func (w *jsonWriter) writeStack(stack Stack, sourceLines []SourceLines) {
+	w.open('[')
+	for j, s := range stack {
+		w.next()
+		w.open('{')
+		w.key("function")
+		w.builder.WriteJSONString(s.Name)
+		w.key("file")
+		w.builder.WriteJSONString(s.File)
+		w.key("line")
+		w.builder.WriteInt(int64(s.Line))
+		if sourceLines != nil {
+			w.key("source")
+			w.open('[')
+			for _, l := range sourceLines[j] {
+				// lines outside of the file are marked by a negative number
+				if l.LineNum < 0 {
+					continue
+				}
+				w.next()
+				w.open('{')
+				w.key("line")
+				w.builder.WriteInt(int64(l.LineNum))
+				w.key("code")
+				w.builder.WriteJSONString(l.Source)
+				w.close('}')
+			}
+			w.close(']')
+		}
+		w.close('}')
+	}
+	w.close(']')
+}
+
+// open opens an object or array.
+func (w *jsonWriter) open(c byte) {
+	w.builder.WriteByte(c)
+	w.counts = append(w.counts, 0)
+}
+
+// close closes the innermost object or array.
+func (w *jsonWriter) close(c byte) {
+	n := w.counts[len(w.counts)-1]
+	w.counts = w.counts[:len(w.counts)-1]
+	if n > 0 {
+		w.newline()
+	}
+	w.builder.WriteByte(c)
+}
+
+// next starts the next value of the innermost object or array.
+func (w *jsonWriter) next() {
+	if w.counts[len(w.counts)-1] > 0 {
+		w.builder.WriteByte(',')
+	}
+	w.counts[len(w.counts)-1]++
+	w.newline()
+}
+
+// key starts the next value of the innermost object with the given key.
+func (w *jsonWriter) key(name string) {
+	w.next()
+	w.builder.WriteJSONString(name)
+	w.builder.WriteByte(':')
+	if w.opts.Pretty {
+		w.builder.WriteByte(' ')
+	}
+}
+
+// newline starts a new line with the indentation of the current nesting level,
+// if the output is pretty-printed.
+func (w *jsonWriter) newline() {
+	if !w.opts.Pretty {
+		return
+	}
+	w.builder.WriteByte('\n')
+	for range w.counts {
+		w.builder.WriteString(w.opts.Indent)
+	}
+}
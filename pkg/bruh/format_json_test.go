@@ -0,0 +1,128 @@
+package bruh_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+type jsonDocument struct {
+	Version   int         `json:"version"`
+	Message   string      `json:"message"`
+	Errors    []jsonError `json:"errors"`
+	CreatedBy *struct {
+		ID    int         `json:"id"`
+		Stack []jsonFrame `json:"stack"`
+	} `json:"created_by"`
+	Stack []jsonFrame `json:"stack"`
+}
+
+type jsonError struct {
+	Type       string         `json:"type"`
+	Kind       string         `json:"kind"`
+	Message    string         `json:"message"`
+	Parent     int            `json:"parent"`
+	Children   []int          `json:"children"`
+	Stack      []jsonFrame    `json:"stack"`
+	Suppressed []jsonDocument `json:"suppressed"`
+}
+
+type jsonFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Source   []struct {
+		Line int    `json:"line"`
+		Code string `json:"code"`
+	} `json:"source"`
+}
+
+func TestJSONFormatter(t *testing.T) {
+	t.Parallel()
+	err := bruh.NewKind(bruh.KindNotFound, "user \"42\"\nnot found")
+	bruh.AddSuppressed(&err, bruh.New("cleanup"))
+	err = bruh.Wrap(bruh.Join(err, errors.New("external")), "handling request")
+
+	t.Run("SingleLine", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		out := bruh.StringFormat(err, bruh.JSONFormatter)
+		assert.False(strings.Contains(out, "\n"), "expected a single line")
+
+		var doc jsonDocument
+		assert.NoError(json.Unmarshal([]byte(out), &doc))
+		assert.Equal(bruh.JSONSchemaVersion, doc.Version)
+		assert.Equal(err.Error(), doc.Message)
+		assert.Len(doc.Errors, 4)
+		assert.Len(doc.Stack, 0)
+
+		assert.Equal("*bruh.Err", doc.Errors[0].Type)
+		assert.Equal("handling request", doc.Errors[0].Message)
+		assert.Equal(-1, doc.Errors[0].Parent)
+		assert.Equal([]int{1}, doc.Errors[0].Children)
+		assert.True(len(doc.Errors[0].Stack) > 0)
+		assert.True(strings.HasSuffix(doc.Errors[0].Stack[0].Function, "TestJSONFormatter"))
+
+		assert.Equal([]int{2, 3}, doc.Errors[1].Children)
+
+		assert.Equal("NotFound", doc.Errors[2].Kind)
+		assert.Equal("user \"42\"\nnot found", doc.Errors[2].Message)
+		assert.Len(doc.Errors[2].Suppressed, 1)
+		assert.Equal(0, doc.Errors[2].Suppressed[0].Version)
+		assert.Equal("cleanup", doc.Errors[2].Suppressed[0].Errors[0].Message)
+
+		assert.Equal("*errors.errorString", doc.Errors[3].Type)
+		assert.Equal(1, doc.Errors[3].Parent)
+	})
+
+	t.Run("Pretty", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		compact := bruh.StringFormat(err, bruh.JSONFormatter)
+		pretty := bruh.StringFormat(err, bruh.NewJSONFormatter(bruh.JSONFormatterOptions{Pretty: true}))
+		var exp bytes.Buffer
+		assert.NoError(json.Indent(&exp, []byte(compact), "", "  "))
+		assert.Equal(exp.String(), pretty)
+
+		pretty = bruh.StringFormat(err, bruh.NewJSONFormatter(bruh.JSONFormatterOptions{Pretty: true, Indent: "\t"}))
+		exp.Reset()
+		assert.NoError(json.Indent(&exp, []byte(compact), "", "\t"))
+		assert.Equal(exp.String(), pretty)
+	})
+
+	t.Run("CombinedStackAndSource", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		f := bruh.NewJSONFormatter(bruh.JSONFormatterOptions{CombinedStack: true, Source: true, SourceContext: 1})
+		var doc jsonDocument
+		assert.NoError(json.Unmarshal([]byte(bruh.StringFormat(err, f)), &doc))
+		assert.Len(doc.Stack, len(err.(*bruh.Err).Stack()))
+		frame := doc.Errors[0].Stack[0]
+		assert.Len(frame.Source, 3)
+		assert.Equal(frame.Line, frame.Source[1].Line)
+		assert.True(strings.Contains(frame.Source[1].Code, "bruh.Wrap(bruh.Join(err"))
+		assert.Len(doc.Stack[0].Source, 3)
+	})
+
+	t.Run("CreatedBy", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		var doc jsonDocument
+		assert.NoError(json.Unmarshal([]byte(bruh.StringFormat(err, bruh.JSONFormatter)), &doc))
+		assert.True(doc.CreatedBy == nil, "expected no creator")
+
+		gerr := spawnFailingGoroutine(func() error { return bruh.New("failed in goroutine") })
+		doc = jsonDocument{}
+		assert.NoError(json.Unmarshal([]byte(bruh.StringFormat(gerr, bruh.JSONFormatter)), &doc))
+		assert.NotNil(doc.CreatedBy)
+		assert.True(doc.CreatedBy.ID > 0)
+		assert.True(len(doc.CreatedBy.Stack) > 0)
+		assert.Equal("github.com/aisbergg/go-bruh/pkg/bruh_test.spawnFailingGoroutine", doc.CreatedBy.Stack[0].Function)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		assert.Equal("", bruh.StringFormat(nil, bruh.JSONFormatter))
+	})
+}
@@ -0,0 +1,281 @@
+package bruh
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/aisbergg/go-bruh/pkg/bruh/fmthelper"
+)
+
+// MarkdownFormatterOptions configures the formatter returned by
+// [NewMarkdownFormatter].
+type MarkdownFormatterOptions struct {
+	// Source adds snippets of the source code to the stack frames, if the
+	// source code is available at runtime. The snippets are written as Go code
+	// blocks and the line of the stack frame is marked by an arrow.
+	Source bool
+	// SourceContext is the number of source lines before and after the line
+	// of a stack frame that are shown, if Source is enabled.
+	SourceContext int
+	// CollapseFrames puts stacks with more than CollapseFrames frames into
+	// collapsible `<details>` blocks, which are supported by GitHub, GitLab and
+	// others. Zero disables collapsing.
+	CollapseFrames int
+}
+
+// MarkdownFormatter is an error formatter that produces Markdown, which can be
+// pasted into issue trackers and chats. The full message is written as a
+// heading, followed by a list of the errors of the chain. The stacks are
+// written as code blocks. Use [NewMarkdownFormatter] to add source snippets or
+// to collapse long stacks.
+//
+// # Output Format
+//
+//	### <full message>
+//
+//	- **`<typeName2>`**: <errorMsg2>
+//	- **`<typeName1>`**: <errorMsg1>
+//
+//	**`<typeName2>`**: <errorMsg2>
+//
+//	```text
+//	at <function1> (<file1>:<line1>)
+//	at <function2> (<file2>:<line2>)
+//	```
+//
+//	**`<typeName1>`**: <errorMsg1>
+//
+//	```text
+//	at <function3> (<file3>:<line3>)
+//	```
+//
+// Errors with a [Kind] are annotated with the kind in brackets after the type
+// name. The errors of an error tree are nested in the list. Suppressed errors,
+// see [AddSuppressed], are written as block quotes below the stack of the
+// error that suppressed them. Special characters of messages are escaped.
+func MarkdownFormatter(b []byte, unpacker *Unpacker) []byte {
+	return formatMarkdown(b, unpacker, &MarkdownFormatterOptions{})
+}
+
+// NewMarkdownFormatter returns a [Formatter] that produces Markdown with the
+// given options. See [MarkdownFormatter] for details.
+//
+// Example usage:
+//
+//	f := bruh.NewMarkdownFormatter(bruh.MarkdownFormatterOptions{Source: true, SourceContext: 2, CollapseFrames: 5})
+//	fmt.Println(bruh.StringFormat(err, f))
+func NewMarkdownFormatter(opts MarkdownFormatterOptions) Formatter {
+	return func(b []byte, unpacker *Unpacker) []byte {
+		return formatMarkdown(b, unpacker, &opts)
+	}
+}
+
+func formatMarkdown(b []byte, unpacker *Unpacker, opts *MarkdownFormatterOptions) []byte {
+	if unpacker.Error() == nil {
+		return b
+	}
+	upkErr := unpacker.Unpack()
+	var sourceLines [][]SourceLines
+	if opts.Source {
+		sourceLines, _ = unpacker.GetSourceLines(opts.SourceContext, 120, false)
+	}
+
+	// allocate a large buffer to avoid later reallocations
+	// message and type: 2x 120 per error
+	// location: 160 per location
+	// source lines: 130 per line
+	builder := fmthelper.New(b)
+	guessCap := len(upkErr) * 240
+	for _, upkElm := range upkErr {
+		guessCap += len(upkElm.PartialStack) * 160
+		if sourceLines != nil {
+			guessCap += len(upkElm.PartialStack) * (2*opts.SourceContext + 1) * 130
+		}
+	}
+	builder.Grow(guessCap)
+	self := func(b []byte, unpacker *Unpacker) []byte {
+		return formatMarkdown(b, unpacker, opts)
+	}
+
+	// heading and list of errors
+	builder.WriteString("### ")
+	writeMarkdownText(builder, Message(unpacker.Error()))
+	builder.WriteByte('\n')
+	for i := range upkErr {
+		if i == 0 {
+			builder.WriteByte('\n')
+		}
+		for range upkErr.BranchLevel(i) {
+			builder.WriteString("  ")
+		}
+		builder.WriteString("- ")
+		writeMarkdownTitle(builder, &upkErr[i])
+		builder.WriteByte('\n')
+	}
+
+	// stacks
+	for i := range upkErr {
+		upkElm := &upkErr[i]
+		if len(upkElm.PartialStack) == 0 && len(upkElm.Suppressed) == 0 {
+			continue
+		}
+		builder.WriteByte('\n')
+		writeMarkdownTitle(builder, upkElm)
+		builder.WriteByte('\n')
+		if len(upkElm.PartialStack) > 0 {
+			var elmSourceLines []SourceLines
+			if sourceLines != nil {
+				elmSourceLines = sourceLines[i]
+			}
+			writeMarkdownStack(builder, upkElm.PartialStack, elmSourceLines, opts)
+		}
+		for _, serr := range upkElm.Suppressed {
+			builder.WriteString("\n> **Suppressed:**\n>\n")
+			sb := AppendStringFormat(nil, serr, self, unpacker.unpackAll)
+			writePrefixed(builder, strings.TrimSuffix(unsafe.String(unsafe.SliceData(sb), len(sb)), "\n"), "> ", "> ")
+			builder.WriteByte('\n')
+		}
+	}
+
+	if gr := unpacker.Goroutine(); gr != nil {
+		if stack := gr.creatorStack(); len(stack) > 0 {
+			builder.WriteString("\n**Created by goroutine")
+			if gr.CreatorID > 0 {
+				builder.WriteByte(' ')
+				builder.WriteInt(int64(gr.CreatorID))
+			}
+			builder.WriteString("**\n")
+			writeMarkdownStack(builder, stack, nil, opts)
+		}
+	}
+	return builder.Bytes()
+}
+
+// writeMarkdownTitle writes the type name, kind and message of the error.
+func writeMarkdownTitle(builder *fmthelper.StringBuilder, upkElm *UnpackedElement) {
+	builder.WriteString("**`")
+	builder.WriteString(typeNameWithKind(upkElm.Err))
+	builder.WriteString("`**: ")
+	if upkElm.Msg != "" {
+		writeMarkdownText(builder, upkElm.Msg)
+	} else {
+		builder.WriteString("\\<no message\\>")
+	}
+}
+
+// writeMarkdownStack writes the stack as a code block, or as a code block per
+// frame if source lines are given. Long stacks are collapsed, see
+// [MarkdownFormatterOptions.CollapseFrames].
+func writeMarkdownStack(
+	builder *fmthelper.StringBuilder,
+	stack Stack,
+	sourceLines []SourceLines,
+	opts *MarkdownFormatterOptions,
+) {
+	collapse := opts.CollapseFrames > 0 && len(stack) > opts.CollapseFrames
+	if collapse {
+		builder.WriteString("\n<details>\n<summary>Stack (")
+		builder.WriteInt(int64(len(stack)))
+		builder.WriteString(" frames)</summary>\n")
+	}
+
+	if sourceLines == nil {
+		var sb strings.Builder
+		for _, s := range stack {
+			sb.WriteString("at ")
+			sb.WriteString(s.Name)
+			sb.WriteString(" (")
+			sb.WriteString(s.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(s.Line))
+			sb.WriteString(")\n")
+		}
+		writeMarkdownCodeBlock(builder, "text", sb.String())
+	} else {
+		for j, s := range stack {
+			builder.WriteString("\n`")
+			builder.WriteString(s.Name)
+			builder.WriteString("` (`")
+			builder.WriteString(s.File)
+			builder.WriteByte(':')
+			builder.WriteInt(int64(s.Line))
+			builder.WriteString("`)\n")
+			lines := sourceLines[j]
+			numDigits := 0
+			for _, l := range lines {
+				numDigits = max(numDigits, fmthelper.DigitsInNumber(l.LineNum))
+			}
+			var sb strings.Builder
+			for k, l := range lines {
+				// lines outside of the file are marked by a negative number
+				if l.LineNum < 0 {
+					continue
+				}
+				if k == opts.SourceContext {
+					sb.WriteString("→ ")
+				} else {
+					sb.WriteString("  ")
+				}
+				for range numDigits - fmthelper.DigitsInNumber(l.LineNum) {
+					sb.WriteByte(' ')
+				}
+				sb.WriteString(strconv.Itoa(l.LineNum))
+				sb.WriteString("│ ")
+				sb.WriteString(l.Source)
+				sb.WriteByte('\n')
+			}
+			writeMarkdownCodeBlock(builder, "go", sb.String())
+		}
+	}
+
+	if collapse {
+		builder.WriteString("\n</details>\n")
+	}
+}
+
+// writeMarkdownCodeBlock writes code as a fenced code block. The fence is
+// longer than any sequence of backticks in the code, so that the code can't
+// end the block.
+func writeMarkdownCodeBlock(builder *fmthelper.StringBuilder, lang, code string) {
+	fenceLen, run := 3, 0
+	for i := range len(code) {
+		if code[i] != '`' {
+			run = 0
+			continue
+		}
+		run++
+		fenceLen = max(fenceLen, run+1)
+	}
+	fence := strings.Repeat("`", fenceLen)
+	builder.WriteByte('\n')
+	builder.WriteString(fence)
+	builder.WriteString(lang)
+	builder.WriteByte('\n')
+	builder.WriteString(code)
+	builder.WriteString(fence)
+	builder.WriteByte('\n')
+}
+
+// writeMarkdownText writes s with the characters that have a special meaning in
+// Markdown escaped by a backslash. Line breaks are replaced by spaces, because
+// s is written into a single line.
+func writeMarkdownText(builder *fmthelper.StringBuilder, s string) {
+	start := 0
+	for i := range len(s) {
+		c := s[i]
+		switch c {
+		case '\\', '`', '*', '_', '{', '}', '[', ']', '<', '>', '(', ')', '#', '+', '-', '.', '!', '|', '~':
+			builder.WriteString(s[start:i])
+			builder.WriteByte('\\')
+			builder.WriteByte(c)
+		case '\n', '\r':
+			builder.WriteString(s[start:i])
+			builder.WriteByte(' ')
+		default:
+			continue
+		}
+		start = i + 1
+	}
+	builder.WriteString(s[start:])
+}
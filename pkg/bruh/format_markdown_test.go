@@ -0,0 +1,55 @@
+package bruh_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+func TestMarkdownFormatter(t *testing.T) {
+	t.Parallel()
+	err := bruh.NewKind(bruh.KindNotFound, "user *42*\nnot found")
+	bruh.AddSuppressed(&err, bruh.New("cleanup"))
+	err = bruh.Wrap(bruh.Join(err, errors.New("external `x`")), "handling request")
+
+	t.Run("Default", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		out := bruh.StringFormat(err, bruh.MarkdownFormatter)
+		assert.True(strings.HasPrefix(out, "### handling request: user \\*42\\* not found external \\`x\\`\n\n"+
+			"- **`*bruh.Err`**: handling request\n"+
+			"- **`*bruh.joinError`**: \\<no message\\>\n"+
+			"  - **`*bruh.Err [NotFound]`**: user \\*42\\* not found\n"+
+			"  - **`*errors.errorString`**: external \\`x\\`\n"+
+			"\n**`*bruh.Err`**: handling request\n\n```text\nat "), out)
+		assert.True(strings.Contains(out, "TestMarkdownFormatter ("))
+		assert.True(strings.Contains(out, "\n> **Suppressed:**\n>\n> ### cleanup\n"))
+		assert.False(strings.Contains(out, "<details>"))
+		assert.False(strings.Contains(out, "```go"))
+	})
+
+	t.Run("SourceAndCollapse", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		f := bruh.NewMarkdownFormatter(bruh.MarkdownFormatterOptions{Source: true, SourceContext: 1, CollapseFrames: 1})
+		out := bruh.StringFormat(err, f)
+		assert.True(strings.Contains(out, "\n<details>\n<summary>Stack (2 frames)</summary>\n"))
+		assert.True(strings.Contains(out, "```go\n"))
+		assert.True(strings.Contains(out, "err = bruh.Wrap(bruh.Join(err, errors.New(\"external `x`\")), \"handling request\")\n"))
+		assert.True(strings.Contains(out, "\n→ "), "expected the line of the frame to be marked")
+	})
+
+	t.Run("FenceLongerThanCode", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		f := bruh.NewMarkdownFormatter(bruh.MarkdownFormatterOptions{Source: true, SourceContext: 1})
+		// the next line contains a fence: ```
+		out := bruh.StringFormat(bruh.New("fence"), f)
+		assert.True(strings.Contains(out, "````go\n"), "expected a fence longer than the one in the code")
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		assert.Equal("", bruh.StringFormat(nil, bruh.MarkdownFormatter))
+	})
+}
@@ -22,6 +22,36 @@ import (
 //	  File "<file2>", line <line2>, in <function2>
 //	  File "<file1>", line <line1>, in <function1>
 //	<typeName1>: <errorMsg1>
+//
+// Errors with a [Kind] are annotated with the kind in brackets after the type
+// name, e.g. `*bruh.Err [NotFound]: errorMsg1`.
+//
+// If an error wraps multiple errors (error tree), the wrapping error is
+// rendered like a Python exception group: its traceback is followed by one
+// numbered and indented section per wrapped error, each framed by `|` and `+`
+// characters.
+//
+// For errors of goroutines started with [Go] or parsed by [ParsePanic], the
+// stack of the creating goroutine is prepended:
+//
+//	Created by goroutine <id> (most recent call last):
+//	  File "<file5>", line <line5>, in <function5>
+//	  File "<file4>", line <line4>, in <function4>
+//
+//	Traceback (most recent call last):
+//	...
+//
+// Suppressed errors, see [AddSuppressed], follow the error that suppressed
+// them, the same way as Python renders exceptions raised while handling
+// another exception:
+//
+//	<typeName1>: <errorMsg1>
+//
+//	During handling of the above exception, another exception occurred:
+//
+//	Traceback (most recent call last):
+//	  File "<file6>", line <line6>, in <function6>
+//	<typeName3>: <errorMsg3>
 func PythonTracebackFormatter(b []byte, unpacker *Unpacker) []byte {
 	return formatPythonTraceback(b, unpacker, false)
 }
@@ -86,36 +116,125 @@ func formatPythonTraceback(b []byte, unpacker *Unpacker, includeSource bool) []b
 		}
 	}
 
-	for i := len(upkErr) - 1; i >= 0; i-- {
-		upkElm := upkErr[i]
-		if len(upkElm.PartialStack) > 0 {
-			builder.WriteString("Traceback (most recent call last):")
-			for j := len(upkElm.PartialStack) - 1; j >= 0; j-- {
-				s := upkElm.PartialStack[j]
+	if gr := unpacker.Goroutine(); gr != nil {
+		if stack := gr.creatorStack(); len(stack) > 0 {
+			builder.WriteString("Created by goroutine")
+			if gr.CreatorID > 0 {
+				builder.WriteByte(' ')
+				builder.WriteInt(int64(gr.CreatorID))
+			}
+			builder.WriteString(" (most recent call last):")
+			for j := len(stack) - 1; j >= 0; j-- {
 				builder.WriteString("\n  File \"")
-				builder.WriteString(s.File)
+				builder.WriteString(stack[j].File)
 				builder.WriteString("\", line ")
-				builder.WriteInt(int64(s.Line))
+				builder.WriteInt(int64(stack[j].Line))
 				builder.WriteString(", in ")
-				builder.WriteString(s.Name)
-				if includeSource {
-					builder.WriteString("\n    ")
-					builder.WriteString(sourceLines[i][j][0].Source)
-				}
+				builder.WriteString(stack[j].Name)
 			}
-			builder.WriteByte('\n')
+			builder.WriteString("\n\n")
 		}
-		builder.WriteString(typeName(upkElm.Err))
-		if upkElm.Msg != "" {
-			builder.WriteString(": ")
-			builder.WriteString(upkElm.Msg)
+	}
+	writePythonTracebackTree(builder, unpacker, upkErr, 0, sourceLines, includeSource)
+	return builder.Bytes()
+}
+
+// writePythonTracebackTree writes the element at index i and all elements
+// wrapped by it. Causes are written before the errors they caused.
+func writePythonTracebackTree(
+	builder *fmthelper.StringBuilder,
+	unpacker *Unpacker,
+	upkErr UnpackedError,
+	i int,
+	sourceLines [][]SourceLines,
+	includeSource bool,
+) {
+	children := upkErr[i].Children
+	switch len(children) {
+	case 0:
+		writePythonTracebackElement(builder, unpacker, upkErr, i, sourceLines, includeSource)
+		return
+	case 1:
+		writePythonTracebackTree(builder, unpacker, upkErr, children[0], sourceLines, includeSource)
+		builder.WriteString(
+			"\n\nThe above exception was the direct cause of the following exception:\n\n",
+		)
+		writePythonTracebackElement(builder, unpacker, upkErr, i, sourceLines, includeSource)
+		return
+	}
+
+	// error wraps multiple errors, so we render it as an exception group
+	sub := fmthelper.New(nil)
+	writePythonTracebackElement(sub, unpacker, upkErr, i, sourceLines, includeSource)
+	writePrefixed(builder, sub.String(), "  + ", "  | ")
+	for k, c := range children {
+		if k == 0 {
+			builder.WriteString("\n  +-+---------------- ")
+		} else {
+			builder.WriteString("\n    +---------------- ")
 		}
+		builder.WriteInt(int64(k + 1))
+		builder.WriteString(" ----------------\n")
+		sub = fmthelper.New(sub.Bytes()[:0])
+		writePythonTracebackTree(sub, unpacker, upkErr, c, sourceLines, includeSource)
+		writePrefixed(builder, sub.String(), "    | ", "    | ")
+	}
+	builder.WriteString("\n    +------------------------------------")
+}
 
-		if i > 0 {
-			builder.WriteString(
-				"\n\nThe above exception was the direct cause of the following exception:\n\n",
-			)
+// writePythonTracebackElement writes the traceback of the element at index i.
+func writePythonTracebackElement(
+	builder *fmthelper.StringBuilder,
+	unpacker *Unpacker,
+	upkErr UnpackedError,
+	i int,
+	sourceLines [][]SourceLines,
+	includeSource bool,
+) {
+	upkElm := upkErr[i]
+	isGroup := len(upkElm.Children) > 1
+	if len(upkElm.PartialStack) > 0 {
+		if isGroup {
+			builder.WriteString("Exception Group Traceback (most recent call last):")
+		} else {
+			builder.WriteString("Traceback (most recent call last):")
+		}
+		for j := len(upkElm.PartialStack) - 1; j >= 0; j-- {
+			s := upkElm.PartialStack[j]
+			builder.WriteString("\n  File \"")
+			builder.WriteString(s.File)
+			builder.WriteString("\", line ")
+			builder.WriteInt(int64(s.Line))
+			builder.WriteString(", in ")
+			builder.WriteString(s.Name)
+			if includeSource {
+				builder.WriteString("\n    ")
+				builder.WriteString(sourceLines[i][j][0].Source)
+			}
+		}
+		builder.WriteByte('\n')
+	}
+	builder.WriteString(typeNameWithKind(upkElm.Err))
+	if upkElm.Msg != "" || isGroup {
+		builder.WriteString(": ")
+		builder.WriteString(upkElm.Msg)
+	}
+	if isGroup {
+		if upkElm.Msg != "" {
+			builder.WriteByte(' ')
+		}
+		builder.WriteByte('(')
+		builder.WriteInt(int64(len(upkElm.Children)))
+		builder.WriteString(" sub-exceptions)")
+	}
+	if len(upkElm.Suppressed) > 0 {
+		f := PythonTracebackFormatter
+		if includeSource {
+			f = FormatPythonTracebackSourced
+		}
+		for _, serr := range upkElm.Suppressed {
+			builder.WriteString("\n\nDuring handling of the above exception, another exception occurred:\n")
+			writeSuppressed(builder, unpacker, []error{serr}, f, "", "")
 		}
 	}
-	return builder.Bytes()
 }
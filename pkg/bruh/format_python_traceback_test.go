@@ -21,6 +21,7 @@ func TestFormatPythonTraceback(t *testing.T) {
 	wrappedExternalInterleavedError := wrappedExternalInterleavedError()
 	externallyWrappedNilError := externallyWrappedNilError()
 	wrappedGlobalError := wrappedGlobalError()
+	joinedError := joinedError()
 
 	assertPythonTraceback := func(name string, err error, exp string) {
 		t.Run(name, func(t *testing.T) {
@@ -143,6 +144,32 @@ Traceback (most recent call last):
   File "/pkg/bruh/format_python_traceback_test.go", line 23, in github.com/aisbergg/go-bruh/pkg/bruh_test.TestFormatPythonTraceback
   File "/pkg/bruh/format_test.go", line 99, in github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedGlobalError
 *bruh.Err: wrapped`)
+	assertPythonTraceback("Joined", joinedError, `  + Exception Group Traceback (most recent call last):
+  |   File "/pkg/bruh/format_test.go", line 367, in github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError
+  | *bruh.joinError: (2 sub-exceptions)
+  +-+---------------- 1 ----------------
+    | Traceback (most recent call last):
+    |   File "/pkg/bruh/format_test.go", line 33, in github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1
+    |   File "/pkg/bruh/format_test.go", line 23, in github.com/aisbergg/go-bruh/pkg/bruh_test.singleRootError
+    | *bruh.Err: root error
+    | 
+    | The above exception was the direct cause of the following exception:
+    | 
+    | Traceback (most recent call last):
+    |   File "/pkg/bruh/format_test.go", line 367, in github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError
+    |   File "/pkg/bruh/format_test.go", line 34, in github.com/aisbergg/go-bruh/pkg/bruh_test.wrappedError1
+    | *bruh.Err: wrapped 1
+    +---------------- 2 ----------------
+    | *errors.errorString: external error
+    +------------------------------------
+
+The above exception was the direct cause of the following exception:
+
+Traceback (most recent call last):
+  File "/testing/testing.go", line 1234, in testing.tRunner
+  File "/pkg/bruh/format_python_traceback_test.go", line 24, in github.com/aisbergg/go-bruh/pkg/bruh_test.TestFormatPythonTraceback
+  File "/pkg/bruh/format_test.go", line 367, in github.com/aisbergg/go-bruh/pkg/bruh_test.joinedError
+*bruh.Err: joined`)
 }
 
 var pythonTracebackRegexpTestingGo = regexp.MustCompile(`testing.go", line \d+`)
@@ -361,3 +361,8 @@ func wrappedError(layers int) error {
 	}
 	return err
 }
+
+//go:noinline
+func joinedError() error {
+	return bruh.Wrap(bruh.Join(wrappedError1(), externalError()), "joined")
+}
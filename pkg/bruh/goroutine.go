@@ -0,0 +1,154 @@
+package bruh
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Go runs fn in a new goroutine and returns a channel that receives the error
+// returned by fn. The channel is buffered, so the goroutine never blocks on
+// it, and it is closed after the error has been sent. Panics in fn are
+// recovered and turned into errors the same way as by [Recover].
+//
+// The stack of the caller of Go is recorded when the goroutine is started.
+// Errors returned by the goroutine carry it along, so that the formatters can
+// render it in a `created by` section. Otherwise, the stack of an error ends
+// at the entry function of the goroutine and the origin of the goroutine is
+// lost. The messages of the errors are not changed.
+//
+// Example usage:
+//
+//	errc := bruh.Go(func() error {
+//	    return doSomething()
+//	})
+//	if err := <-errc; err != nil {
+//	    fmt.Println(bruh.String(err))
+//	}
+func Go(fn func() error) <-chan error {
+	errc := make(chan error, 1)
+	gerr := newGoroutineErr(1)
+	go func() {
+		defer close(errc)
+		errc <- gerr.run(fn)
+	}()
+	return errc
+}
+
+// GoWaitGroup behaves like [Go], but the goroutine is tracked by wg instead of
+// returning a channel. Non-nil errors are passed to onError, which is called
+// before the goroutine is marked as done. Therefore, all errors have been
+// handled once wg.Wait returns. onError may be called concurrently by
+// different goroutines.
+//
+// Example usage:
+//
+//	var (
+//	    wg   sync.WaitGroup
+//	    mu   sync.Mutex
+//	    errs []error
+//	)
+//	for _, job := range jobs {
+//	    bruh.GoWaitGroup(&wg, job.Run, func(err error) {
+//	        mu.Lock()
+//	        errs = append(errs, err)
+//	        mu.Unlock()
+//	    })
+//	}
+//	wg.Wait()
+func GoWaitGroup(wg *sync.WaitGroup, fn func() error, onError func(err error)) {
+	wg.Add(1)
+	gerr := newGoroutineErr(1)
+	go func() {
+		defer wg.Done()
+		if err := gerr.run(fn); err != nil {
+			onError(err)
+		}
+	}()
+}
+
+// goroutineErr attaches the goroutine, in which an error occurred, and its
+// creator to the error. It neither has a message nor a stack of its own and is
+// merged into the wrapped error when unpacked.
+type goroutineErr struct { //nolint: errname
+	err           error
+	id            int
+	creatorID     int
+	creator       []uintptr
+	goroutine     *Goroutine
+	goroutineOnce sync.Once
+}
+
+// newGoroutineErr records the stack of the creating goroutine. The given
+// number of callers of the caller of newGoroutineErr are skipped.
+func newGoroutineErr(skip int) *goroutineErr {
+	gerr := &goroutineErr{creatorID: currentGoroutineID()}
+	var pcs [maxStackCaptureDepth]uintptr
+	// skips this method, runtime.Callers and user defined number of other
+	// callers
+	n := runtime.Callers(2+max(skip, 0), pcs[:stackCaptureDepth()])
+	gerr.creator = slices.Clone(pcs[:n])
+	return gerr
+}
+
+// run runs fn and returns its error, if any, annotated with the goroutine.
+func (e *goroutineErr) run(fn func() error) (err error) {
+	defer func() {
+		if err != nil {
+			e.err = err
+			e.id = currentGoroutineID()
+			err = e
+		}
+	}()
+	defer Recover(&err)
+	return fn()
+}
+
+// Error returns the message of the wrapped error.
+func (e *goroutineErr) Error() string {
+	return e.err.Error()
+}
+
+// Format implements the fmt.Formatter interface. See [Err.Format] for details.
+func (e *goroutineErr) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+// Unwrap returns the wrapped error.
+func (e *goroutineErr) Unwrap() error {
+	return e.err
+}
+
+// Goroutine returns the goroutine the error occurred in, including the stack
+// of the creating goroutine.
+func (e *goroutineErr) Goroutine() *Goroutine {
+	e.goroutineOnce.Do(func() {
+		creator := make(Stack, max(len(e.creator), DefaultErrorStackDepth))
+		creator = creator[:stackPC(e.creator).toStack(creator)]
+		e.goroutine = &Goroutine{
+			ID:        e.id,
+			CreatorID: e.creatorID,
+		}
+		if len(creator) > 0 {
+			e.goroutine.CreatedBy = &creator[0]
+			e.goroutine.Creator = creator
+		}
+	})
+	return e.goroutine
+}
+
+// currentGoroutineID returns the ID of the calling goroutine or zero if it
+// cannot be determined. The runtime doesn't expose the ID, so it is parsed from
+// the header of the goroutine's stack trace.
+func currentGoroutineID() int {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	header := buf[:n]
+	if idx := bytes.IndexByte(header, '\n'); idx >= 0 {
+		header = header[:idx]
+	}
+	id, _, _ := parseGoroutineHeader(string(header))
+	return id
+}
@@ -0,0 +1,145 @@
+package bruh_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+//go:noinline
+func spawnFailingGoroutine(fn func() error) error {
+	return <-bruh.Go(fn)
+}
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoError", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		errc := bruh.Go(func() error { return nil })
+		assert.NoError(<-errc)
+		_, open := <-errc
+		assert.False(open)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		cause := bruh.New("failed in goroutine")
+		err := spawnFailingGoroutine(func() error { return cause })
+		assert.Error(err)
+		assert.Equal("failed in goroutine", err.Error())
+		assert.True(errors.Is(err, cause))
+
+		gerr, ok := err.(interface{ Goroutine() *bruh.Goroutine })
+		assert.True(ok)
+		gr := gerr.Goroutine()
+		assert.True(gr.ID > 0)
+		assert.True(gr.CreatorID > 0)
+		assert.True(gr.ID != gr.CreatorID)
+		assert.True(len(gr.Creator) >= 2)
+		assert.Equal("github.com/aisbergg/go-bruh/pkg/bruh_test.spawnFailingGoroutine", gr.CreatedBy.Name)
+		assert.Equal(gr.Creator[0], *gr.CreatedBy)
+		assert.True(strings.HasPrefix(gr.Creator[1].Name, "github.com/aisbergg/go-bruh/pkg/bruh_test.TestGo"), gr.Creator[1].Name)
+		// the frames of the launcher are hidden
+		assert.False(strings.Contains(bruh.String(err), "goroutineErr"), bruh.String(err))
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := spawnFailingGoroutine(func() error { panic("panic in goroutine") })
+		assert.Error(err)
+		assert.Equal("panic in goroutine", err.Error())
+		value, ok := bruh.PanicValue(err)
+		assert.True(ok)
+		assert.Equal("panic in goroutine", value)
+	})
+}
+
+func TestGoWaitGroup(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	onError := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+	bruh.GoWaitGroup(&wg, func() error { return nil }, onError)
+	bruh.GoWaitGroup(&wg, func() error { return bruh.New("failed") }, onError)
+	bruh.GoWaitGroup(&wg, func() error { panic("panicked") }, onError)
+	wg.Wait()
+
+	assert.Len(errs, 2)
+	msgs := []string{errs[0].Error(), errs[1].Error()}
+	assert.True(
+		(msgs[0] == "failed" && msgs[1] == "panicked") || (msgs[0] == "panicked" && msgs[1] == "failed"),
+		strings.Join(msgs, ", "),
+	)
+}
+
+func TestFormatGoroutineErr(t *testing.T) {
+	t.Parallel()
+	err := spawnFailingGoroutine(func() error { return bruh.New("failed in goroutine") })
+
+	assertFormat := func(name string, formatter bruh.Formatter, expected ...string) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			result := bruh.StringFormat(err, formatter)
+			for _, exp := range expected {
+				if !strings.Contains(result, exp) {
+					t.Errorf("expected output to contain:\n|%s|\n\ngot:\n|%s|", exp, result)
+				}
+			}
+		})
+	}
+
+	const spawner = "github.com/aisbergg/go-bruh/pkg/bruh_test.spawnFailingGoroutine"
+	assertFormat("Bruh", bruh.BruhFormatter, "\ncreated by goroutine ", "\n    at "+spawner+" (")
+	assertFormat("BruhStacked", bruh.BruhStackedFormatter, "\ncreated by goroutine ", "\n    at "+spawner+" (")
+	assertFormat("GoPanic", bruh.GoPanicFormatter, "\ncreated by "+spawner+" in goroutine ", "\n[originating from goroutine ")
+	assertFormat("GoRuntimePanic", bruh.GoRuntimePanicFormatter,
+		"\ncreated by "+spawner+" in goroutine ",
+		"\n[originating from goroutine ",
+		"\n"+spawner+"()\n",
+	)
+	assertFormat("JavaStackTrace", bruh.JavaStackTraceFormatter, "\nCreated by: goroutine ", "\n    at "+spawner+" (")
+	assertFormat("PythonTraceback", bruh.PythonTracebackFormatter, "Created by goroutine ", ", in "+spawner+"\n\nTraceback")
+
+	t.Run("Reparse", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		p, perr := bruh.ParsePanic(strings.NewReader(bruh.StringFormat(err, bruh.GoRuntimePanicFormatter)))
+		assert.NoError(perr)
+		assert.Len(p.Goroutines, 1)
+		assert.Equal(spawner, p.Goroutines[0].CreatedBy.Name)
+		assert.True(len(p.Goroutines[0].Creator) >= 2)
+		assert.Equal(spawner, p.Goroutines[0].Creator[0].Name)
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		report := bruh.NewReport(err)
+		assert.NotNil(report.Errors[0].Goroutine)
+		assert.NotNil(report.CreatedBy)
+		assert.True(report.CreatedBy.ID > 0)
+		assert.Equal(spawner, report.CreatedBy.Stack[0].Function)
+
+		data, jerr := json.Marshal(report)
+		assert.NoError(jerr)
+		assert.True(strings.Contains(string(data), `"created_by":{"id":`), string(data))
+		report, jerr = bruh.UnmarshalReport(data)
+		assert.NoError(jerr)
+		for _, f := range []bruh.Formatter{bruh.GoRuntimePanicFormatter, bruh.BruhFormatter, bruh.JSONFormatter} {
+			assert.Equal(bruh.StringFormat(err, f), bruh.StringFormat(report.Err(), f))
+		}
+	})
+}
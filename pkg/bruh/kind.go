@@ -0,0 +1,236 @@
+package bruh
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// Kind classifies errors independently of their messages and types, e.g. to
+// map them to HTTP status codes or to decide whether an operation can be
+// retried. Kinds are attached to errors on creation with [NewKind] or
+// [WrapKind] and looked up with [KindOf]. Foreign errors, which cannot carry a
+// kind, are classified by registering them with [RegisterKind] or
+// [RegisterKindType].
+//
+// The predefined kinds are modeled after the canonical gRPC status codes. Own
+// kinds can be defined as needed:
+//
+//	const KindPaymentRequired bruh.Kind = "PaymentRequired"
+type Kind string
+
+// Predefined kinds.
+const (
+	// KindUnknown is the kind of errors that are not classified.
+	KindUnknown Kind = ""
+	// KindNotFound indicates that a requested entity was not found.
+	KindNotFound Kind = "NotFound"
+	// KindAlreadyExists indicates that an entity to be created already exists.
+	KindAlreadyExists Kind = "AlreadyExists"
+	// KindConflict indicates that an operation conflicts with the current state,
+	// e.g. a concurrent modification.
+	KindConflict Kind = "Conflict"
+	// KindInvalidArgument indicates that the caller specified an invalid
+	// argument.
+	KindInvalidArgument Kind = "InvalidArgument"
+	// KindUnauthenticated indicates that the caller could not be
+	// authenticated.
+	KindUnauthenticated Kind = "Unauthenticated"
+	// KindPermissionDenied indicates that the caller is not permitted to
+	// execute the operation.
+	KindPermissionDenied Kind = "PermissionDenied"
+	// KindResourceExhausted indicates that a resource, like a quota or the disk
+	// space, is exhausted.
+	KindResourceExhausted Kind = "ResourceExhausted"
+	// KindUnavailable indicates that a service is currently unavailable. The
+	// operation can usually be retried.
+	KindUnavailable Kind = "Unavailable"
+	// KindDeadlineExceeded indicates that a deadline expired before the
+	// operation could complete.
+	KindDeadlineExceeded Kind = "DeadlineExceeded"
+	// KindCanceled indicates that the operation was canceled by the caller.
+	KindCanceled Kind = "Canceled"
+	// KindUnimplemented indicates that the operation is not implemented or not
+	// supported.
+	KindUnimplemented Kind = "Unimplemented"
+	// KindInternal indicates an internal error, e.g. a broken invariant.
+	KindInternal Kind = "Internal"
+)
+
+// NewKind creates a new [Err] with the given kind and message.
+func NewKind(kind Kind, msg string) error {
+	berr := NewSkip(1, msg)
+	berr.kind = kind
+	return berr
+}
+
+// WrapKind wraps the given error by creating a new [Err] with the given kind
+// and message. The kind takes precedence over the kinds of the wrapped errors.
+// If the given error is nil, nil is returned.
+func WrapKind(err error, kind Kind, msg string) error {
+	if err == nil {
+		return nil
+	}
+	berr := WrapSkip(err, 1, msg)
+	berr.kind = kind
+	return berr
+}
+
+// KindOf returns the kind of the given error chain. The kind of the outermost
+// error in the chain that has a kind takes precedence. If the chain branches
+// into an error tree, the branches are searched depth-first. If no error in the
+// chain has a kind, the chain is classified by the registered foreign errors,
+// see [RegisterKind]. Otherwise, [KindUnknown] is returned.
+func KindOf(err error) Kind {
+	if err == nil {
+		return KindUnknown
+	}
+	if kind := explicitKind(err); kind != KindUnknown {
+		return kind
+	}
+	return registeredKind(err)
+}
+
+// IsKind reports whether the kind of the given error chain is kind, see
+// [KindOf].
+func IsKind(err error, kind Kind) bool {
+	return KindOf(err) == kind
+}
+
+// explicitKind returns the first kind found in the error tree of err.
+func explicitKind(err error) Kind {
+	for err != nil {
+		if k, ok := err.(kinder); ok {
+			if kind := k.Kind(); kind != KindUnknown {
+				return kind
+			}
+		}
+		switch u := err.(type) {
+		case unwraper:
+			err = u.Unwrap()
+		case multiUnwraper:
+			for _, uerr := range u.Unwrap() {
+				if kind := explicitKind(uerr); kind != KindUnknown {
+					return kind
+				}
+			}
+			return KindUnknown
+		default:
+			return KindUnknown
+		}
+	}
+	return KindUnknown
+}
+
+// elementKind returns the kind of a single error without considering the
+// errors it wraps, except for foreign errors, which are classified by the
+// registry.
+func elementKind(err error) Kind {
+	if k, ok := err.(kinder); ok {
+		return k.Kind()
+	}
+	return registeredKind(err)
+}
+
+// typeNameWithKind returns the type name of the error followed by its kind in
+// brackets, e.g. `*bruh.Err [NotFound]`.
+func typeNameWithKind(err error) string {
+	name := typeName(err)
+	if kind := elementKind(err); kind != KindUnknown {
+		return name + " [" + string(kind) + "]"
+	}
+	return name
+}
+
+// -----------------------------------------------------------------------------
+//
+// Registry
+//
+// -----------------------------------------------------------------------------
+
+// kindRegistration maps foreign errors to a kind.
+type kindRegistration struct {
+	matches func(err error) bool
+	kind    Kind
+}
+
+var (
+	kindRegistryMu sync.RWMutex
+	kindRegistry   = []kindRegistration{
+		{matchesTarget(context.Canceled), KindCanceled},
+		{matchesTarget(context.DeadlineExceeded), KindDeadlineExceeded},
+		{matchesTarget(os.ErrDeadlineExceeded), KindDeadlineExceeded},
+		{matchesTarget(fs.ErrNotExist), KindNotFound},
+		{matchesTarget(fs.ErrExist), KindAlreadyExists},
+		{matchesTarget(fs.ErrPermission), KindPermissionDenied},
+		{matchesTarget(errors.ErrUnsupported), KindUnimplemented},
+	}
+)
+
+// RegisterKind registers the kind of a foreign sentinel error, so that chains
+// containing it are classified by [KindOf], even if no error in the chain has a
+// kind. The target is matched with [errors.Is]. Registrations are checked in
+// reverse order, thus later registrations take precedence. The following
+// errors are registered by default:
+//
+//   - [context.Canceled]: [KindCanceled]
+//   - [context.DeadlineExceeded], [os.ErrDeadlineExceeded]: [KindDeadlineExceeded]
+//   - [fs.ErrNotExist]: [KindNotFound]
+//   - [fs.ErrExist]: [KindAlreadyExists]
+//   - [fs.ErrPermission]: [KindPermissionDenied]
+//   - [errors.ErrUnsupported]: [KindUnimplemented]
+//
+// Errors of packages that this package doesn't depend on, like database/sql,
+// are not registered, so that these packages are not linked into every
+// program. The errors of database/sql are registered by importing package
+// [github.com/aisbergg/go-bruh/pkg/bruh/kindsql]. Register others yourself, if
+// you use them.
+//
+// Example usage:
+//
+//	bruh.RegisterKind(redis.Nil, bruh.KindNotFound)
+func RegisterKind(target error, kind Kind) {
+	registerKind(matchesTarget(target), kind)
+}
+
+// RegisterKindType registers the kind of a foreign error type T, so that chains
+// containing an error of that type are classified by [KindOf]. The type is
+// matched with [errors.As]. See [RegisterKind] for details.
+//
+// Example usage:
+//
+//	bruh.RegisterKindType[*json.SyntaxError](bruh.KindInvalidArgument)
+func RegisterKindType[T error](kind Kind) {
+	registerKind(func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}, kind)
+}
+
+func registerKind(matches func(err error) bool, kind Kind) {
+	kindRegistryMu.Lock()
+	kindRegistry = append(kindRegistry, kindRegistration{matches: matches, kind: kind})
+	kindRegistryMu.Unlock()
+}
+
+// matchesTarget returns a matcher for the registry that matches chains
+// containing the target.
+func matchesTarget(target error) func(err error) bool {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+// registeredKind classifies the error chain by the registered foreign errors.
+func registeredKind(err error) Kind {
+	kindRegistryMu.RLock()
+	defer kindRegistryMu.RUnlock()
+	for i := len(kindRegistry) - 1; i >= 0; i-- {
+		if kindRegistry[i].matches(err) {
+			return kindRegistry[i].kind
+		}
+	}
+	return KindUnknown
+}
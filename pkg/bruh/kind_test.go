@@ -0,0 +1,119 @@
+package bruh_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+	_ "github.com/aisbergg/go-bruh/pkg/bruh/kindsql"
+)
+
+// kindTestError is a foreign error type that is registered in the kind registry.
+type kindTestError struct{}
+
+func (kindTestError) Error() string { return "kind test error" }
+
+// errKindTestSentinel is a foreign sentinel error that is registered in the
+// kind registry.
+var errKindTestSentinel = errors.New("kind test sentinel")
+
+func init() {
+	bruh.RegisterKind(errKindTestSentinel, bruh.KindUnavailable)
+	bruh.RegisterKindType[kindTestError](bruh.KindResourceExhausted)
+}
+
+func TestKindOf(t *testing.T) {
+	t.Parallel()
+
+	assertKind := func(name string, err error, expected bruh.Kind) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			assert.Equal(expected, bruh.KindOf(err))
+			assert.True(bruh.IsKind(err, expected))
+		})
+	}
+
+	notFound := bruh.NewKind(bruh.KindNotFound, "user not found")
+	assertKind("Nil", nil, bruh.KindUnknown)
+	assertKind("NoKind", bruh.New("no kind"), bruh.KindUnknown)
+	assertKind("ForeignWithoutKind", errors.New("foreign"), bruh.KindUnknown)
+	assertKind("NewKind", notFound, bruh.KindNotFound)
+	assertKind("Wrapped", bruh.Wrap(notFound, "wrapped"), bruh.KindNotFound)
+	assertKind("WrappedForeign", fmt.Errorf("foreign: %w", notFound), bruh.KindNotFound)
+	assertKind("OuterKindWins", bruh.WrapKind(notFound, bruh.KindInternal, "wrapped"), bruh.KindInternal)
+	assertKind("WrapKindNil", bruh.WrapKind(nil, bruh.KindInternal, "wrapped"), bruh.KindUnknown)
+	assertKind("Joined", bruh.Join(bruh.New("no kind"), notFound), bruh.KindNotFound)
+	assertKind("ExplicitBeforeRegistered", bruh.WrapKind(sql.ErrNoRows, bruh.KindInternal, "query"), bruh.KindInternal)
+
+	// registered by default
+	assertKind("ContextCanceled", bruh.Wrap(context.Canceled, "request"), bruh.KindCanceled)
+	assertKind("ContextDeadlineExceeded", bruh.Wrap(context.DeadlineExceeded, "request"), bruh.KindDeadlineExceeded)
+	assertKind("OSDeadlineExceeded", os.ErrDeadlineExceeded, bruh.KindDeadlineExceeded)
+	assertKind("FSNotExist", bruh.Wrap(&fs.PathError{Op: "open", Path: "/x", Err: fs.ErrNotExist}, "opening"), bruh.KindNotFound)
+	assertKind("FSExist", fs.ErrExist, bruh.KindAlreadyExists)
+	assertKind("FSPermission", fs.ErrPermission, bruh.KindPermissionDenied)
+	assertKind("Unsupported", errors.ErrUnsupported, bruh.KindUnimplemented)
+
+	// registered by the test
+	assertKind("RegisteredSentinel", bruh.Wrap(errKindTestSentinel, "calling"), bruh.KindUnavailable)
+	assertKind("RegisteredType", bruh.Wrap(kindTestError{}, "calling"), bruh.KindResourceExhausted)
+	assertKind("SQLNoRows", bruh.Wrap(sql.ErrNoRows, "query"), bruh.KindNotFound)
+}
+
+func TestFormatKind(t *testing.T) {
+	t.Parallel()
+	err := bruh.WrapKind(bruh.Wrap(sql.ErrNoRows, "query"), bruh.KindInternal, "loading user")
+
+	assertFormat := func(name string, formatter bruh.Formatter, expected ...string) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			result := bruh.StringFormat(err, formatter, true)
+			for _, exp := range expected {
+				if !strings.Contains(result, exp) {
+					t.Errorf("expected output to contain:\n|%s|\n\ngot:\n|%s|", exp, result)
+				}
+			}
+		})
+	}
+
+	assertFormat("JavaStackTrace", bruh.JavaStackTraceFormatter,
+		"*bruh.Err [Internal]: loading user\n",
+		"Caused by: *bruh.Err: query\n",
+		"Caused by: *errors.errorString [NotFound]: sql: no rows in result set",
+	)
+	assertFormat("PythonTraceback", bruh.PythonTracebackFormatter,
+		"\n*bruh.Err [Internal]: loading user",
+		"\n*bruh.Err: query",
+		"*errors.errorString [NotFound]: sql: no rows in result set",
+	)
+	assertFormat("BruhStackedTyped", bruh.BruhStackedFancyFormatter(false, false, true),
+		"*bruh.Err [Internal]: loading user\n",
+		"\n*errors.errorString [NotFound]: sql: no rows in result set",
+	)
+
+	t.Run("Report", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		data, merr := json.Marshal(bruh.NewReport(err, true))
+		assert.NoError(merr)
+		report, uerr := bruh.UnmarshalReport(data)
+		assert.NoError(uerr)
+		assert.Equal(bruh.KindInternal, report.Errors[0].Kind)
+		assert.Equal(bruh.KindUnknown, report.Errors[1].Kind)
+		assert.Equal(bruh.KindNotFound, report.Errors[2].Kind)
+		rerr := report.Err()
+		assert.Equal(bruh.KindInternal, bruh.KindOf(rerr))
+		assert.Equal(
+			bruh.StringFormat(err, bruh.JavaStackTraceFormatter, true),
+			bruh.StringFormat(rerr, bruh.JavaStackTraceFormatter, true),
+		)
+	})
+}
@@ -0,0 +1,21 @@
+// Package kindsql registers the kinds of the errors of package database/sql,
+// see [bruh.RegisterKind]. They are not registered by package bruh itself, so
+// that database/sql is not linked into every program. Import this package for
+// its side effects to register them:
+//
+//	import _ "github.com/aisbergg/go-bruh/pkg/bruh/kindsql"
+//
+// The following errors are registered:
+//
+//   - [sql.ErrNoRows]: [bruh.KindNotFound]
+package kindsql
+
+import (
+	"database/sql"
+
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+func init() {
+	bruh.RegisterKind(sql.ErrNoRows, bruh.KindNotFound)
+}
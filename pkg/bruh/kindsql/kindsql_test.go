@@ -0,0 +1,18 @@
+package kindsql_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+	_ "github.com/aisbergg/go-bruh/pkg/bruh/kindsql"
+)
+
+func TestRegisteredKinds(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	assert.Equal(bruh.KindNotFound, bruh.KindOf(sql.ErrNoRows))
+	assert.Equal(bruh.KindNotFound, bruh.KindOf(bruh.Wrap(sql.ErrNoRows, "loading user")))
+}
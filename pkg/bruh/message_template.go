@@ -0,0 +1,116 @@
+package bruh
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemplateArg is an argument of a formatted message. Pass it to [Errorf],
+// [Wrapf] and their variants to give the argument a name, which is used when
+// the arguments are exported, e.g. as log attributes. It is formatted the same
+// way as its value.
+type TemplateArg struct {
+	// Name is the name of the argument. It is empty for positional arguments.
+	Name string
+	// Value is the value of the argument.
+	Value any
+}
+
+// Arg returns a named argument for a formatted message.
+//
+// Example usage:
+//
+//	err := bruh.Errorf("user %s not found", bruh.Arg("user_id", id))
+func Arg(name string, value any) TemplateArg {
+	return TemplateArg{Name: name, Value: value}
+}
+
+// Format implements the fmt.Formatter interface. It formats the value of the
+// argument.
+func (a TemplateArg) Format(s fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(s, fmt.FormatString(s, verb), a.Value)
+}
+
+// MessageTemplate is the template of a formatted message along with the
+// arguments that were used to render the message. Unlike the rendered message,
+// the template is the same for all errors of the same origin, which makes it
+// suitable to group errors, e.g. in logs.
+type MessageTemplate struct {
+	// Template is the format string of the message, as passed to [Errorf] or
+	// [Wrapf]. For messages that are not formatted, it is the message itself,
+	// with percent signs escaped.
+	Template string
+	// Args are the arguments of the message. Arguments that are not of type
+	// [TemplateArg] are positional and have no name.
+	Args []TemplateArg
+}
+
+// MessageTemplate returns the template of the single message of this error,
+// without the messages of wrapped errors.
+func (e *Err) MessageTemplate() MessageTemplate {
+	if e.lazyMsg == nil || e.lazyMsg.fn != nil {
+		return MessageTemplate{Template: escapeTemplate(e.Message())}
+	}
+	args := make([]TemplateArg, len(e.lazyMsg.args))
+	for i, arg := range e.lazyMsg.args {
+		if targ, ok := arg.(TemplateArg); ok {
+			args[i] = targ
+		} else {
+			args[i] = TemplateArg{Value: arg}
+		}
+	}
+	return MessageTemplate{Template: e.lazyMsg.format, Args: args}
+}
+
+// MessageTemplateOf returns the template of the full message of the error
+// chain, as returned by err.Error(). The templates of the errors are joined
+// the same way as their messages and the arguments are concatenated. The
+// messages of errors that don't provide a template, are included as they are.
+// If err is nil, an empty template is returned.
+func MessageTemplateOf(err error) MessageTemplate {
+	var tmpl MessageTemplate
+	var sb strings.Builder
+	for err != nil {
+		if werr, ok := err.(*wrapErrors); ok {
+			// the errors wrapped by multiple %w verbs of [Errorf]
+			for i, child := range werr.errs {
+				if i == 0 && sb.Len() > 0 {
+					sb.WriteString(": ")
+				} else if i > 0 {
+					sb.WriteByte('\n')
+				}
+				cur := MessageTemplateOf(child)
+				sb.WriteString(cur.Template)
+				tmpl.Args = append(tmpl.Args, cur.Args...)
+			}
+			break
+		}
+		mt, ok := err.(messageTemplater)
+		if _, multi := err.(multiUnwraper); !ok || multi {
+			if msg := err.Error(); msg != "" {
+				if sb.Len() > 0 {
+					sb.WriteString(": ")
+				}
+				sb.WriteString(escapeTemplate(msg))
+			}
+			break
+		}
+		cur := mt.MessageTemplate()
+		if cur.Template != "" {
+			if sb.Len() > 0 {
+				sb.WriteString(": ")
+			}
+			sb.WriteString(cur.Template)
+		}
+		tmpl.Args = append(tmpl.Args, cur.Args...)
+		err = Unwrap(err)
+	}
+	tmpl.Template = sb.String()
+	return tmpl
+}
+
+// escapeTemplate escapes the percent signs of the message, so that it can be
+// used as a template.
+func escapeTemplate(msg string) string {
+	return strings.ReplaceAll(msg, "%", "%%")
+}
@@ -0,0 +1,49 @@
+package bruh_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	inner := bruh.Errorf("user %s not found in %q", bruh.Arg("user_id", "u1"), "db")
+	err := bruh.Wrapf(bruh.Wrap(inner, "100% failed"), "request %d", 42)
+	assert.Equal(`request 42: 100% failed: user u1 not found in "db"`, err.Error())
+
+	assert.Equal(bruh.MessageTemplate{
+		Template: "user %s not found in %q",
+		Args:     []bruh.TemplateArg{{Name: "user_id", Value: "u1"}, {Value: "db"}},
+	}, inner.(*bruh.Err).MessageTemplate())
+	assert.Equal(bruh.MessageTemplate{Template: "100%% failed"}, bruh.Unwrap(err).(*bruh.Err).MessageTemplate())
+	assert.Equal(
+		bruh.MessageTemplate{Template: "message"},
+		bruh.WrapFunc(inner, func() string { return "message" }).(*bruh.Err).MessageTemplate(),
+	)
+
+	tmpl := bruh.MessageTemplateOf(err)
+	assert.Equal(`request %d: 100%% failed: user %s not found in %q`, tmpl.Template)
+	assert.Equal([]bruh.TemplateArg{{Value: 42}, {Name: "user_id", Value: "u1"}, {Value: "db"}}, tmpl.Args)
+	// the template renders the full message
+	args := make([]any, 0, len(tmpl.Args))
+	for _, arg := range tmpl.Args {
+		args = append(args, arg)
+	}
+	assert.Equal(err.Error(), fmt.Sprintf(tmpl.Template, args...))
+
+	// errors without template are included as they are
+	tmpl = bruh.MessageTemplateOf(bruh.Wrapf(fmt.Errorf("external %d%%", 1), "id %d", 2))
+	assert.Equal(`id %d: external 1%%`, tmpl.Template)
+	tmpl = bruh.MessageTemplateOf(bruh.Wrap(bruh.Join(errors.New("a"), bruh.Errorf("b %d", 1)), "joined"))
+	assert.Equal("joined: a\nb 1", tmpl.Template)
+	assert.Equal(bruh.MessageTemplate{}, bruh.MessageTemplateOf(nil))
+
+	// named arguments are formatted like their values
+	assert.Equal("  42|0x2a", fmt.Sprintf("%4v|%#x", bruh.Arg("a", 42), bruh.Arg("a", 42)))
+}
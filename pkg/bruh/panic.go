@@ -0,0 +1,125 @@
+package bruh
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+)
+
+// panicSearchDepth is the number of frames between the capture of the stack
+// and the start of the panic that are searched for the panic boundary.
+const panicSearchDepth = 32
+
+// PanicErr is an error that was created from a recovered panic by
+// [NewFromPanic] or [Recover]. Its stack trace starts at the site of the panic
+// and it retains the original panic value. The stack is always captured, up to
+// the depth of the process-wide stack capture policy, regardless of its mode,
+// see [SetStackCapturePolicy].
+type PanicErr struct { //nolint: errname
+	Err
+	value any
+}
+
+// newPanicErr creates a new [PanicErr] from the given panic value. If the
+// panic is in flight, the stack is recorded from the start of the panic.
+// Otherwise, the given number of callers of the caller of newPanicErr are
+// skipped.
+func newPanicErr(panicValue any, skip int) *PanicErr {
+	perr := &PanicErr{value: panicValue}
+	if err, ok := panicValue.(error); ok {
+		perr.msg = "panic"
+		perr.err = err
+	} else {
+		perr.msg = fmt.Sprint(panicValue)
+	}
+
+	// skips this method and runtime.Callers
+	var pcs [maxStackCaptureDepth + panicSearchDepth]uintptr
+	n := runtime.Callers(2, pcs[:stackCaptureDepth()+panicSearchDepth])
+	start := min(max(skip, 0), n)
+	// The deferred function that recovers from the panic is called by the
+	// runtime function that starts the panic. Everything below it is the stack
+	// of the panicking goroutine at the time of the panic.
+	for i, pc := range pcs[:min(n, panicSearchDepth)] {
+		if fn := runtime.FuncForPC(pc); fn != nil && fn.Name() == "runtime.gopanic" {
+			start = i
+			break
+		}
+	}
+	perr.stack = slices.Clone(pcs[start:min(n, start+stackCaptureDepth())])
+	return perr
+}
+
+// PanicValue returns the original value that was passed to panic.
+func (e *PanicErr) PanicValue() any {
+	return e.value
+}
+
+// Format implements the fmt.Formatter interface. See [Err.Format] for details.
+func (e *PanicErr) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+// Stack returns a combined stack trace of all errors in the chain, starting at
+// the site of the panic.
+func (e *PanicErr) Stack() Stack {
+	stack := *newChainStack()
+	stack = stack[:combinedStack(e, stack)]
+	return stack
+}
+
+// StackFrames is an alias for [*PanicErr.Stack].
+func (e *PanicErr) StackFrames() Stack {
+	return e.Stack()
+}
+
+// Recover recovers from a panic and stores it as error in the variable pointed
+// to by errp, replacing any error stored there. It must be called directly by a
+// defer statement, otherwise it doesn't stop the panic. If the goroutine is not
+// panicking, errp is left untouched. The error is created the same way as by
+// [NewFromPanic].
+//
+// Example usage:
+//
+//	func doSomething() (err error) {
+//	    defer bruh.Recover(&err)
+//	    panic("something went wrong")
+//	}
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if err, ok := r.(error); ok {
+		if _, ok := err.(interface{ bruhError() }); ok {
+			*errp = err
+			return
+		}
+	}
+	*errp = newPanicErr(r, 1)
+}
+
+// Repanic panics again with the original value of the [PanicErr] in the chain
+// of err. If the chain contains no [PanicErr], it panics with err itself. If err
+// is nil, Repanic does nothing.
+func Repanic(err error) {
+	if err == nil {
+		return
+	}
+	var perr *PanicErr
+	if errors.As(err, &perr) {
+		panic(perr.value)
+	}
+	panic(err)
+}
+
+// PanicValue returns the original panic value of the first [PanicErr] in the
+// chain of err and true, or nil and false if the chain contains no [PanicErr].
+func PanicValue(err error) (any, bool) {
+	var perr *PanicErr
+	if errors.As(err, &perr) {
+		return perr.value, true
+	}
+	return nil, false
+}
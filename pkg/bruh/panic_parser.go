@@ -0,0 +1,261 @@
+package bruh
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Panic is the parsed textual output of a Go panic or crash, as returned by
+// [ParsePanic].
+type Panic struct {
+	// Message is the panic message without the `panic: ` or `fatal error: `
+	// prefix. It can span multiple lines, e.g. for nested panics.
+	Message string `json:"message"`
+	// Fatal is true if the output is a fatal error of the Go runtime instead of
+	// a panic.
+	Fatal bool `json:"fatal,omitempty"`
+	// Goroutines are the goroutines in the order they appear in the output. The
+	// first goroutine is usually the one that panicked.
+	Goroutines []Goroutine `json:"goroutines,omitempty"`
+}
+
+// Goroutine is a single goroutine of a parsed [Panic].
+type Goroutine struct {
+	// ID is the ID of the goroutine. It is zero if the output contains no
+	// goroutine header, e.g. for the output of [GoPanicFormatter].
+	ID int `json:"id,omitempty"`
+	// State is the state of the goroutine as stated in the goroutine header,
+	// e.g. `running` or `chan receive, 2 minutes`.
+	State string `json:"state,omitempty"`
+	// Stack is the stack of the goroutine. The frames contain no program
+	// counters, but the offsets of the `+0x` suffixes in [StackFrame.Offset].
+	Stack Stack `json:"stack,omitempty"`
+	// CreatedBy is the frame of the `go` statement that created the goroutine.
+	// It is nil if the output does not state the creator.
+	CreatedBy *StackFrame `json:"created_by,omitempty"`
+	// CreatorID is the ID of the goroutine that created this goroutine. It is
+	// zero if unknown.
+	CreatorID int `json:"creator_id,omitempty"`
+	// Creator is the stack of the creating goroutine at the time this goroutine
+	// was created. It is recorded by [Go] and printed by the runtime with
+	// GODEBUG=tracebackancestors=N. The first frame is the same as CreatedBy.
+	Creator Stack `json:"creator,omitempty"`
+}
+
+// creatorStack returns the stack of the creating goroutine, which consists of
+// the CreatedBy frame only, if the full stack is unknown.
+func (g *Goroutine) creatorStack() Stack {
+	if len(g.Creator) > 0 {
+		return g.Creator
+	}
+	if g.CreatedBy != nil {
+		return Stack{*g.CreatedBy}
+	}
+	return nil
+}
+
+// ParsePanic parses the textual output of a Go panic or crash, e.g. from
+// container logs, the output of [runtime/debug.Stack] or the output of
+// [GoPanicFormatter]. It recognizes the panic message, goroutine headers,
+// function names with their file:line locations and `+0x` offsets, `created by`
+// lines and the stack of the creating goroutine printed with
+// GODEBUG=tracebackancestors=N. Only the stack of the direct ancestor is kept.
+// Unrecognized lines within a goroutine are skipped.
+//
+// Use [Panic.Err] to render the parsed panic with any [Formatter]. An error is
+// returned if the input cannot be read or contains neither a panic message
+// nor any stack frames.
+func ParsePanic(r io.Reader) (*Panic, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, Wrap(err, "reading panic output")
+	}
+
+	p := &Panic{}
+	var msgLines []string
+	var gr *Goroutine
+	// ancestors is the number of ancestor stacks seen in the current goroutine
+	ancestors := 0
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if id, state, ok := parseGoroutineHeader(line); ok {
+			p.Goroutines = append(p.Goroutines, Goroutine{ID: id, State: state})
+			gr = &p.Goroutines[len(p.Goroutines)-1]
+			ancestors = 0
+			continue
+		}
+		if gr != nil && strings.HasPrefix(line, "[originating from goroutine ") {
+			ancestors++
+			continue
+		}
+
+		// function followed by its location
+		if i+1 < len(lines) && !strings.HasPrefix(line, "\t") {
+			if frame, ok := parseFrameLocation(lines[i+1]); ok {
+				if gr == nil {
+					// output without goroutine header, e.g. from GoPanicFormatter
+					p.Goroutines = append(p.Goroutines, Goroutine{})
+					gr = &p.Goroutines[len(p.Goroutines)-1]
+				}
+				i++
+				if ancestors > 0 {
+					// frames of the ancestors, only the direct one is kept
+					if ancestors == 1 && !strings.HasPrefix(line, "created by ") {
+						frame.Name = parseFunctionName(line)
+						gr.Creator = append(gr.Creator, frame)
+					}
+					continue
+				}
+				if fn, ok := strings.CutPrefix(line, "created by "); ok {
+					fn, creator, _ := strings.Cut(fn, " in goroutine ")
+					frame.Name = fn
+					gr.CreatedBy = &frame
+					gr.CreatorID, _ = strconv.Atoi(creator)
+					continue
+				}
+				frame.Name = parseFunctionName(line)
+				gr.Stack = append(gr.Stack, frame)
+				continue
+			}
+		}
+
+		// everything before the first goroutine is part of the message
+		if gr == nil {
+			msgLines = append(msgLines, line)
+		}
+	}
+
+	// trim empty lines around the message
+	for len(msgLines) > 0 && strings.TrimSpace(msgLines[0]) == "" {
+		msgLines = msgLines[1:]
+	}
+	for len(msgLines) > 0 && strings.TrimSpace(msgLines[len(msgLines)-1]) == "" {
+		msgLines = msgLines[:len(msgLines)-1]
+	}
+	if len(msgLines) > 0 {
+		if msg, ok := strings.CutPrefix(msgLines[0], "panic: "); ok {
+			msgLines[0] = msg
+		} else if msg, ok := strings.CutPrefix(msgLines[0], "fatal error: "); ok {
+			msgLines[0] = msg
+			p.Fatal = true
+		}
+	}
+	p.Message = strings.Join(msgLines, "\n")
+
+	if p.Message == "" && len(p.Goroutines) == 0 {
+		return nil, New("input contains no panic")
+	}
+	return p, nil
+}
+
+// parseGoroutineHeader parses a goroutine header, e.g.
+// `goroutine 1 [running]:` or `goroutine 1 gp=0xc000002380 m=0 mp=0x5bc2e0 [running]:`.
+func parseGoroutineHeader(line string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(line, "goroutine ")
+	if !ok || !strings.HasSuffix(rest, "]:") {
+		return 0, "", false
+	}
+	idStr, rest, ok := strings.Cut(rest, " ")
+	if !ok {
+		return 0, "", false
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", false
+	}
+	stateStart := strings.IndexByte(rest, '[')
+	if stateStart < 0 {
+		return 0, "", false
+	}
+	return id, rest[stateStart+1 : len(rest)-2], true
+}
+
+// parseFrameLocation parses the location line of a frame, e.g.
+// `	/path/to/file.go:12 +0x1d`. The returned frame has no name.
+func parseFrameLocation(line string) (StackFrame, bool) {
+	line, ok := strings.CutPrefix(line, "\t")
+	if !ok {
+		return StackFrame{}, false
+	}
+	location := line
+	var offset uint64
+	if idx := strings.Index(line, " +0x"); idx >= 0 {
+		location = line[:idx]
+		offsetStr, _, _ := strings.Cut(line[idx+4:], " ")
+		var err error
+		if offset, err = strconv.ParseUint(offsetStr, 16, 64); err != nil {
+			return StackFrame{}, false
+		}
+	}
+	sep := strings.LastIndexByte(location, ':')
+	if sep <= 0 {
+		return StackFrame{}, false
+	}
+	lineNum, err := strconv.Atoi(location[sep+1:])
+	if err != nil {
+		return StackFrame{}, false
+	}
+	return StackFrame{
+		File:   location[:sep],
+		Line:   lineNum,
+		Offset: uintptr(offset),
+	}, true
+}
+
+// parseFunctionName returns the function name of a function line, e.g.
+// `main.(*T).do(0xc000012345, {0x4a1e40?, 0x5439f0?})`, without the argument
+// list.
+func parseFunctionName(line string) string {
+	if !strings.HasSuffix(line, ")") {
+		return line
+	}
+	depth := 0
+	for i := len(line) - 1; i >= 0; i-- {
+		switch line[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// Err returns the panicked goroutine as an error that can be formatted with any
+// [Formatter]. The stack is that of the first goroutine, without calls of the
+// runtime, the same way as stacks of errors are recorded. The site of the panic
+// is marked by a frame named [PanicFrameName]. The type name of the
+// error is `panic` or `fatal error`. If the panic has no goroutines, the error
+// has no stack.
+func (p *Panic) Err() error {
+	rerr := &RemoteErr{
+		msg: p.Message,
+		typ: "panic",
+	}
+	if p.Fatal {
+		rerr.typ = "fatal error"
+	}
+	if len(p.Goroutines) > 0 {
+		rerr.goroutine = &p.Goroutines[0]
+		stack := make(Stack, 0, len(p.Goroutines[0].Stack))
+		for _, frame := range p.Goroutines[0].Stack {
+			// exclude runtime calls
+			if _, ok := stackFrameName(frame.Name, frame.File); !ok {
+				continue
+			}
+			stack = append(stack, frame)
+		}
+		rerr.stack = stack
+	}
+	return rerr
+}
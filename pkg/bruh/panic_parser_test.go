@@ -0,0 +1,152 @@
+package bruh_test
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+const runtimePanicOutput = `panic: something went wrong [recovered]
+	panic: something went wrong again
+
+goroutine 18 [running]:
+panic({0x4a1e40?, 0x5439f0?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.(*worker).do(0xc000012345, {0x4a1e40, 0x5})
+	/app/worker.go:42 +0x1d
+main.run.func1()
+	/app/main.go:17 +0x25
+created by main.run in goroutine 1
+	/app/main.go:15 +0x4f
+
+goroutine 1 gp=0xc000002380 m=0 mp=0x5bc2e0 [chan receive, 2 minutes]:
+main.run(...)
+	/app/main.go:20
+main.main()
+	/app/main.go:9 +0x18
+exit status 2
+`
+
+// ancestorsPanicOutput is the output of a panic with
+// GODEBUG=tracebackancestors=5.
+const ancestorsPanicOutput = `panic: boom
+
+goroutine 6 [running]:
+main.work()
+	/app/main.go:5 +0x25
+created by main.spawn in goroutine 1
+	/app/main.go:8 +0x1a
+[originating from goroutine 1]:
+main.spawn()
+	/app/main.go:9 +0x1a
+main.main()
+	/app/main.go:13 +0x13
+`
+
+func TestParsePanic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RuntimePanic", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		p, err := bruh.ParsePanic(strings.NewReader(runtimePanicOutput))
+		assert.NoError(err)
+		assert.Equal("something went wrong [recovered]\n\tpanic: something went wrong again", p.Message)
+		assert.False(p.Fatal)
+		assert.Len(p.Goroutines, 2)
+
+		gr := p.Goroutines[0]
+		assert.Equal(18, gr.ID)
+		assert.Equal("running", gr.State)
+		assert.Equal(bruh.Stack{
+			{Name: "panic", File: "/usr/local/go/src/runtime/panic.go", Line: 770, Offset: 0x132},
+			{Name: "main.(*worker).do", File: "/app/worker.go", Line: 42, Offset: 0x1d},
+			{Name: "main.run.func1", File: "/app/main.go", Line: 17, Offset: 0x25},
+		}, gr.Stack)
+		assert.Equal(&bruh.StackFrame{Name: "main.run", File: "/app/main.go", Line: 15, Offset: 0x4f}, gr.CreatedBy)
+		assert.Equal(1, gr.CreatorID)
+
+		gr = p.Goroutines[1]
+		assert.Equal(1, gr.ID)
+		assert.Equal("chan receive, 2 minutes", gr.State)
+		assert.Equal(bruh.Stack{
+			{Name: "main.run", File: "/app/main.go", Line: 20},
+			{Name: "main.main", File: "/app/main.go", Line: 9, Offset: 0x18},
+		}, gr.Stack)
+		assert.True(gr.CreatedBy == nil, "expected no creator")
+	})
+
+	t.Run("FatalError", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		p, err := bruh.ParsePanic(strings.NewReader("fatal error: all goroutines are asleep - deadlock!\n\n" +
+			"goroutine 1 [chan receive]:\nmain.main()\n\t/app/main.go:5 +0x1d\n"))
+		assert.NoError(err)
+		assert.True(p.Fatal)
+		assert.Equal("all goroutines are asleep - deadlock!", p.Message)
+		assert.Equal("fatal error", bruh.StringFormat(p.Err(), bruh.JavaStackTraceFormatter)[:11])
+	})
+
+	t.Run("DebugStack", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		p, err := bruh.ParsePanic(bytes.NewReader(debug.Stack()))
+		assert.NoError(err)
+		assert.Equal("", p.Message)
+		assert.Len(p.Goroutines, 1)
+		stack := p.Err().(*bruh.RemoteErr).Frames()
+		assert.Equal("github.com/aisbergg/go-bruh/pkg/bruh_test.TestParsePanic.func3", stack[0].Name)
+	})
+
+	t.Run("GoPanicFormatter", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := wrappedError3()
+		p, perr := bruh.ParsePanic(strings.NewReader(bruh.StringFormat(err, bruh.GoPanicFormatter)))
+		assert.NoError(perr)
+		assert.Equal(err.Error(), p.Message)
+		assert.Len(p.Goroutines, 1)
+		assert.Equal(0, p.Goroutines[0].ID)
+		assert.Equal(
+			bruh.StringFormat(bruh.NewReport(err).Err(), bruh.BruhFormatter),
+			bruh.StringFormat(p.Err(), bruh.BruhFormatter),
+		)
+	})
+
+	t.Run("Ancestors", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		p, err := bruh.ParsePanic(strings.NewReader(ancestorsPanicOutput))
+		assert.NoError(err)
+		assert.Len(p.Goroutines, 1)
+		gr := p.Goroutines[0]
+		assert.Len(gr.Stack, 1)
+		assert.Equal("main.spawn", gr.CreatedBy.Name)
+		assert.Equal(1, gr.CreatorID)
+		assert.Equal(bruh.Stack{
+			{Name: "main.spawn", File: "/app/main.go", Line: 9, Offset: 0x1a},
+			{Name: "main.main", File: "/app/main.go", Line: 13, Offset: 0x13},
+		}, gr.Creator)
+		assert.Equal(ancestorsPanicOutput, bruh.StringFormat(p.Err(), bruh.GoRuntimePanicFormatter)+"\n")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		_, err := bruh.ParsePanic(strings.NewReader("\n\n"))
+		assert.Error(err)
+	})
+}
+
+func TestParsePanicErr(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	p, err := bruh.ParsePanic(strings.NewReader(runtimePanicOutput))
+	assert.NoError(err)
+	assert.Equal(`something went wrong [recovered]
+	panic: something went wrong again
+    at panic (/usr/local/go/src/runtime/panic.go:770)
+    at main.(*worker).do (/app/worker.go:42)
+    at main.run.func1 (/app/main.go:17)
+created by goroutine 1
+    at main.run (/app/main.go:15)`, bruh.String(p.Err()))
+}
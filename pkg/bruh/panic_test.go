@@ -0,0 +1,180 @@
+package bruh_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+//go:noinline
+func panickingFunction(value any) {
+	panic(value)
+}
+
+// recoverWithNewFromPanic recovers in a nested helper, so that the recover
+// site is far away from the panic site.
+func recoverWithNewFromPanic(value any) (err error) {
+	defer func() {
+		err = recoverHelper(recover())
+	}()
+	panickingFunction(value)
+	return nil
+}
+
+//go:noinline
+func recoverHelper(r any) error {
+	return bruh.NewFromPanic(r)
+}
+
+func recoverWithRecover(value any) (err error) {
+	defer bruh.Recover(&err)
+	panickingFunction(value)
+	return nil
+}
+
+func TestPanicErr(t *testing.T) {
+	t.Parallel()
+
+	assertPanicStack := func(name string, recoverFn func(any) error) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			err := recoverFn("something went wrong")
+			assert.Error(err)
+			assert.Equal("something went wrong", err.Error())
+			var perr *bruh.PanicErr
+			assert.True(errors.As(err, &perr))
+			assert.Equal("something went wrong", perr.PanicValue())
+
+			stack := perr.Stack()
+			assert.True(len(stack) >= 3, "stack too short")
+			assert.Equal(bruh.PanicFrameName, stack[0].Name)
+			assert.True(strings.HasSuffix(stack[1].Name, ".panickingFunction"), stack[1].Name)
+			assert.True(strings.HasPrefix(stack[2].Name, "github.com/aisbergg/go-bruh/pkg/bruh_test.recoverWith"), stack[2].Name)
+		})
+	}
+
+	assertPanicStack("NewFromPanic", recoverWithNewFromPanic)
+	assertPanicStack("Recover", recoverWithRecover)
+
+	t.Run("ErrorValue", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		cause := errors.New("cause")
+		err := recoverWithRecover(cause)
+		assert.Equal("panic: cause", err.Error())
+		assert.True(errors.Is(err, cause))
+		value, ok := bruh.PanicValue(err)
+		assert.True(ok)
+		assert.Equal(cause, value)
+	})
+
+	t.Run("Wrapped", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := bruh.Wrap(recoverWithRecover(42), "wrapped")
+		value, ok := bruh.PanicValue(err)
+		assert.True(ok)
+		assert.Equal(42, value)
+		assert.Equal(bruh.PanicFrameName, err.(*bruh.Err).Stack()[0].Name)
+	})
+
+	t.Run("NoPanic", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		_, ok := bruh.PanicValue(bruh.New("no panic"))
+		assert.False(ok)
+		err := func() (err error) {
+			defer bruh.Recover(&err)
+			return nil
+		}()
+		assert.NoError(err)
+	})
+}
+
+func TestFormatPanicErr(t *testing.T) {
+	t.Parallel()
+	err := recoverWithRecover("something went wrong")
+
+	assertFormat := func(name string, formatter bruh.Formatter, expected ...string) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			result := bruh.StringFormat(err, formatter)
+			for _, exp := range expected {
+				if !strings.Contains(result, exp) {
+					t.Errorf("expected output to contain:\n|%s|\n\ngot:\n|%s|", exp, result)
+				}
+			}
+		})
+	}
+
+	assertFormat("Bruh", bruh.BruhFormatter, "something went wrong\n    at panic (", "panic.go:")
+	assertFormat("GoRuntimePanic", bruh.GoRuntimePanicFormatter,
+		"panic: something went wrong\n\ngoroutine 1 [running]:\npanic()\n",
+		"\ngithub.com/aisbergg/go-bruh/pkg/bruh_test.panickingFunction()\n",
+	)
+	assertFormat("JavaStackTrace", bruh.JavaStackTraceFormatter, "\n    at panic (")
+
+	// the header of a panic that was recovered and caused another panic must
+	// match the one printed by the runtime
+	t.Run("GoRuntimePanicRepanicked", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRepanickingChild$")
+		cmd.Env = append(os.Environ(), "BRUH_TEST_REPANIC=1")
+		output, cerr := cmd.CombinedOutput()
+		assert.Error(cerr)
+		expected, _, found := strings.Cut(string(output), "\n\ngoroutine ")
+		assert.True(found, "expected panic output, got:\n"+string(output))
+		assert.Equal("panic: something went wrong [recovered]\n\tpanic: handling: something went wrong", expected)
+
+		repanicked := recoverWithRecover(fmt.Errorf("handling: %w", err))
+		result := bruh.StringFormat(repanicked, bruh.GoRuntimePanicFormatter)
+		assert.True(strings.HasPrefix(result, expected+"\n\ngoroutine 1 [running]:\n"), "unexpected output:\n"+result)
+		p, perr := bruh.ParsePanic(strings.NewReader(result))
+		assert.NoError(perr)
+		assert.Equal(strings.TrimPrefix(expected, "panic: "), p.Message)
+	})
+}
+
+// TestRepanickingChild is executed in a child process by
+// [TestFormatPanicErr]. It recovers a panic and panics again while handling it.
+// The panic happens in a separate goroutine, so that it isn't re-panicked by
+// the test runner.
+func TestRepanickingChild(t *testing.T) {
+	if os.Getenv("BRUH_TEST_REPANIC") == "" {
+		t.Skip("only executed as child process")
+	}
+	go func() {
+		defer func() {
+			panic(fmt.Errorf("handling: %v", recover()))
+		}()
+		panickingFunction("something went wrong")
+	}()
+	select {}
+}
+
+func TestRepanic(t *testing.T) {
+	t.Parallel()
+
+	assertRepanic := func(name string, err error, expected any) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			var value any
+			func() {
+				defer func() { value = recover() }()
+				bruh.Repanic(err)
+			}()
+			assert.Equal(expected, value)
+		})
+	}
+
+	assertRepanic("Nil", nil, nil)
+	assertRepanic("PanicValue", recoverWithRecover("value"), "value")
+	assertRepanic("WrappedPanicValue", bruh.Wrap(recoverWithRecover(42), "wrapped"), 42)
+	err := bruh.New("no panic")
+	assertRepanic("Error", err, err)
+}
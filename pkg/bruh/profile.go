@@ -0,0 +1,300 @@
+package bruh
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// activeErrorProfile is the profile that records the errors. It is nil if no
+// profile is active.
+var activeErrorProfile atomic.Pointer[ErrorProfile]
+
+// errorProfileSample is the number and size of the errors created at a call
+// site.
+type errorProfileSample struct {
+	stack []uintptr
+	count int64
+	bytes int64
+}
+
+// ErrorProfile records which call sites create errors, similar to how heap
+// profiles record allocations. Errors are counted by their stacks, see
+// [Err.Callers]. Only errors created through [NewSkip] and [WrapSkip] are
+// recorded, which includes [New], [Errorf], [Wrap] and [Wrapf]. The profile can
+// be written in the pprof format to be analyzed with `go tool pprof`.
+//
+// Example usage:
+//
+//	profile := bruh.StartErrorProfile()
+//	defer func() {
+//	    profile.Stop()
+//	    f, _ := os.Create("errors.pprof")
+//	    defer f.Close()
+//	    _, _ = profile.WriteTo(f)
+//	}()
+type ErrorProfile struct {
+	mu    sync.Mutex
+	start time.Time
+	end   time.Time
+	// samples are the samples by call site. The key is the raw memory of the
+	// stack.
+	samples map[string]*errorProfileSample
+}
+
+// StartErrorProfile starts recording the creation of errors and returns the
+// profile. Only one profile records at a time; a previously started profile is
+// stopped. Recording adds a small overhead to the creation of every error, so
+// it should only be enabled while profiling.
+func StartErrorProfile() *ErrorProfile {
+	p := &ErrorProfile{
+		start:   time.Now(),
+		samples: make(map[string]*errorProfileSample),
+	}
+	if prev := activeErrorProfile.Swap(p); prev != nil {
+		prev.stopped()
+	}
+	return p
+}
+
+// Stop stops recording errors. The recorded samples are retained and can still
+// be written. Stopping a profile that is not recording has no effect.
+func (p *ErrorProfile) Stop() {
+	if activeErrorProfile.CompareAndSwap(p, nil) {
+		p.stopped()
+	}
+}
+
+// stopped records the end of the profile.
+func (p *ErrorProfile) stopped() {
+	p.mu.Lock()
+	p.end = time.Now()
+	p.mu.Unlock()
+}
+
+// record records the creation of the given error.
+func (p *ErrorProfile) record(e *Err) {
+	var pcs [maxStackCaptureDepth]uintptr
+	stack := e.appendCallers(pcs[:0], 0)
+	key := unsafe.String(
+		(*byte)(unsafe.Pointer(unsafe.SliceData(stack))),
+		len(stack)*int(unsafe.Sizeof(uintptr(0))),
+	)
+	size := int64(unsafe.Sizeof(*e)) + int64(len(e.stack))*int64(unsafe.Sizeof(uintptr(0))) + int64(len(e.msg))
+
+	p.mu.Lock()
+	sample, ok := p.samples[key]
+	if !ok {
+		sample = &errorProfileSample{stack: slices.Clone(stack)}
+		p.samples[strings.Clone(key)] = sample
+	}
+	sample.count++
+	sample.bytes += size
+	p.mu.Unlock()
+}
+
+// recordError records the creation of the given error in the active profile,
+// if any.
+func recordError(e *Err) {
+	if p := activeErrorProfile.Load(); p != nil {
+		p.record(e)
+	}
+}
+
+// WriteTo writes the profile in the gzip compressed protocol buffer format of
+// pprof to w. The profile contains two sample types: `errors/count`, the
+// number of errors created, and `space/bytes`, the memory used by them. The
+// program counters are symbolized using the running binary. It implements
+// [io.WriterTo].
+func (p *ErrorProfile) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	start, end := p.start, p.end
+	samples := make([]errorProfileSample, 0, len(p.samples))
+	for _, sample := range p.samples {
+		samples = append(samples, *sample)
+	}
+	p.mu.Unlock()
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	b := newProfileBuilder()
+	b.valueType(protoProfileSampleType, "errors", "count")
+	b.valueType(protoProfileSampleType, "space", "bytes")
+	locIDs := make([]uint64, 0, DefaultErrorStackDepth)
+	for i := range samples {
+		locIDs = locIDs[:0]
+		for _, pc := range samples[i].stack {
+			if id := b.location(pc); id != 0 {
+				locIDs = append(locIDs, id)
+			}
+		}
+		msgStart := b.enc.startMessage()
+		b.enc.uint64s(protoSampleLocationID, locIDs)
+		b.enc.int64s(protoSampleValue, []int64{samples[i].count, samples[i].bytes})
+		b.enc.endMessage(protoProfileSample, msgStart)
+	}
+	data := b.finish(start, end)
+
+	cw := &countingWriter{w: w}
+	zw := gzip.NewWriter(cw)
+	if _, err := zw.Write(data); err != nil {
+		return cw.n, Wrap(err, "writing error profile")
+	}
+	if err := zw.Close(); err != nil {
+		return cw.n, Wrap(err, "writing error profile")
+	}
+	return cw.n, nil
+}
+
+// profileBuilder builds a pprof profile. The samples are encoded right away,
+// the locations and functions are collected and encoded at the end.
+type profileBuilder struct {
+	enc       protoEncoder
+	funcs     protoEncoder
+	locs      protoEncoder
+	strings   []string
+	stringIDs map[string]int64
+	locIDs    map[uintptr]uint64
+	funcIDs   map[string]uint64
+}
+
+// newProfileBuilder creates a new [profileBuilder].
+func newProfileBuilder() *profileBuilder {
+	b := &profileBuilder{
+		stringIDs: make(map[string]int64),
+		locIDs:    make(map[uintptr]uint64),
+		funcIDs:   make(map[string]uint64),
+	}
+	// the first entry of the string table must be the empty string
+	b.stringID("")
+	return b
+}
+
+// stringID returns the index of s in the string table, adding it if needed.
+func (b *profileBuilder) stringID(s string) int64 {
+	if id, ok := b.stringIDs[s]; ok {
+		return id
+	}
+	id := int64(len(b.strings))
+	b.strings = append(b.strings, s)
+	b.stringIDs[s] = id
+	return id
+}
+
+// valueType encodes a ValueType message in the given field of the profile.
+func (b *profileBuilder) valueType(field int, typ, unit string) {
+	start := b.enc.startMessage()
+	b.enc.int64(protoValueTypeType, b.stringID(typ))
+	b.enc.int64(protoValueTypeUnit, b.stringID(unit))
+	b.enc.endMessage(field, start)
+}
+
+// location returns the ID of the location of the given program counter,
+// adding it if needed. Inlined functions are encoded as multiple lines of the
+// location, the innermost function first. Zero is returned if the program
+// counter cannot be symbolized.
+func (b *profileBuilder) location(pc uintptr) uint64 {
+	if id, ok := b.locIDs[pc]; ok {
+		return id
+	}
+	frames := runtime.CallersFrames([]uintptr{pc})
+	id := uint64(len(b.locIDs) + 1)
+	start := b.locs.startMessage()
+	b.locs.uint64(protoLocationID, id)
+	b.locs.uint64(protoLocationMappingID, 1)
+	first := true
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "" {
+			if !more {
+				break
+			}
+			continue
+		}
+		if first {
+			b.locs.uint64(protoLocationAddress, uint64(frame.PC))
+			first = false
+		}
+		lineStart := b.locs.startMessage()
+		b.locs.uint64(protoLineFunctionID, b.function(frame.Function, frame.File))
+		b.locs.int64(protoLineLine, int64(frame.Line))
+		b.locs.endMessage(protoLocationLine, lineStart)
+		if !more {
+			break
+		}
+	}
+	if first {
+		// the program counter couldn't be symbolized
+		b.locs.data = b.locs.data[:start]
+		b.locIDs[pc] = 0
+		return 0
+	}
+	b.locs.endMessage(protoProfileLocation, start)
+	b.locIDs[pc] = id
+	return id
+}
+
+// function returns the ID of the given function, adding it if needed.
+func (b *profileBuilder) function(name, file string) uint64 {
+	if id, ok := b.funcIDs[name]; ok {
+		return id
+	}
+	id := uint64(len(b.funcIDs) + 1)
+	start := b.funcs.startMessage()
+	b.funcs.uint64(protoFunctionID, id)
+	b.funcs.int64(protoFunctionName, b.stringID(name))
+	b.funcs.int64(protoFunctionSystemName, b.stringID(name))
+	b.funcs.int64(protoFunctionFilename, b.stringID(file))
+	b.funcs.endMessage(protoProfileFunction, start)
+	b.funcIDs[name] = id
+	return id
+}
+
+// finish encodes the remaining parts of the profile and returns it.
+func (b *profileBuilder) finish(start, end time.Time) []byte {
+	// a single mapping for the running binary, which is already symbolized
+	exe, _ := os.Executable()
+	info := getBinaryInfo()
+	mappingStart := b.enc.startMessage()
+	b.enc.uint64(protoMappingID, 1)
+	b.enc.int64(protoMappingFilename, b.stringID(exe))
+	b.enc.int64(protoMappingBuildID, b.stringID(info.BuildID))
+	b.enc.bool(protoMappingHasFunctions, true)
+	b.enc.bool(protoMappingHasFilenames, true)
+	b.enc.bool(protoMappingHasLineNumbers, true)
+	b.enc.bool(protoMappingHasInlineFrames, true)
+	b.enc.endMessage(protoProfileMapping, mappingStart)
+
+	b.enc.data = append(b.enc.data, b.locs.data...)
+	b.enc.data = append(b.enc.data, b.funcs.data...)
+	b.enc.int64(protoProfileTimeNanos, start.UnixNano())
+	b.enc.int64(protoProfileDurationNanos, end.Sub(start).Nanoseconds())
+	b.valueType(protoProfilePeriodType, "errors", "count")
+	b.enc.int64(protoProfilePeriod, 1)
+	b.enc.int64(protoProfileDefaultSampleType, b.stringID("errors"))
+	for _, s := range b.strings {
+		b.enc.string(protoProfileStringTable, s)
+	}
+	return b.enc.data
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements the io.Writer interface.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,153 @@
+package bruh
+
+// This file contains a minimal encoder for the protocol buffer format of pprof
+// profiles, see
+// https://github.com/google/pprof/blob/main/proto/profile.proto. It is hand
+// written to keep the package free of third-party dependencies.
+
+// Field numbers of the profile.proto messages.
+const (
+	// Profile
+	protoProfileSampleType        = 1
+	protoProfileSample            = 2
+	protoProfileMapping           = 3
+	protoProfileLocation          = 4
+	protoProfileFunction          = 5
+	protoProfileStringTable       = 6
+	protoProfileTimeNanos         = 9
+	protoProfileDurationNanos     = 10
+	protoProfilePeriodType        = 11
+	protoProfilePeriod            = 12
+	protoProfileDefaultSampleType = 14
+
+	// ValueType
+	protoValueTypeType = 1
+	protoValueTypeUnit = 2
+
+	// Sample
+	protoSampleLocationID = 1
+	protoSampleValue      = 2
+
+	// Mapping
+	protoMappingID              = 1
+	protoMappingFilename        = 5
+	protoMappingBuildID         = 6
+	protoMappingHasFunctions    = 7
+	protoMappingHasFilenames    = 8
+	protoMappingHasLineNumbers  = 9
+	protoMappingHasInlineFrames = 10
+
+	// Location
+	protoLocationID        = 1
+	protoLocationMappingID = 2
+	protoLocationAddress   = 3
+	protoLocationLine      = 4
+
+	// Line
+	protoLineFunctionID = 1
+	protoLineLine       = 2
+
+	// Function
+	protoFunctionID         = 1
+	protoFunctionName       = 2
+	protoFunctionSystemName = 3
+	protoFunctionFilename   = 4
+)
+
+// Wire types of the protocol buffer encoding.
+const (
+	protoWireVarint = 0
+	protoWireBytes  = 2
+)
+
+// protoEncoder encodes protocol buffer messages. Nested messages are encoded
+// in place and prefixed with their length once they are complete.
+type protoEncoder struct {
+	data []byte
+}
+
+// varint appends x in the varint encoding.
+func (e *protoEncoder) varint(x uint64) {
+	for x >= 0x80 {
+		e.data = append(e.data, byte(x)|0x80)
+		x >>= 7
+	}
+	e.data = append(e.data, byte(x))
+}
+
+// key appends the key of a field with the given number and wire type.
+func (e *protoEncoder) key(field, wireType int) {
+	e.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+// uint64 appends an unsigned integer field. Zero values are omitted.
+func (e *protoEncoder) uint64(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+	e.key(field, protoWireVarint)
+	e.varint(x)
+}
+
+// int64 appends a signed integer field. Zero values are omitted.
+func (e *protoEncoder) int64(field int, x int64) {
+	e.uint64(field, uint64(x))
+}
+
+// bool appends a boolean field. False values are omitted.
+func (e *protoEncoder) bool(field int, x bool) {
+	if x {
+		e.uint64(field, 1)
+	}
+}
+
+// string appends a string field. Unlike the other fields, empty strings are not
+// omitted, because they are significant in repeated fields.
+func (e *protoEncoder) string(field int, s string) {
+	e.key(field, protoWireBytes)
+	e.varint(uint64(len(s)))
+	e.data = append(e.data, s...)
+}
+
+// uint64s appends a packed repeated unsigned integer field.
+func (e *protoEncoder) uint64s(field int, xs []uint64) {
+	if len(xs) == 0 {
+		return
+	}
+	start := e.startMessage()
+	for _, x := range xs {
+		e.varint(x)
+	}
+	e.endMessage(field, start)
+}
+
+// int64s appends a packed repeated signed integer field.
+func (e *protoEncoder) int64s(field int, xs []int64) {
+	if len(xs) == 0 {
+		return
+	}
+	start := e.startMessage()
+	for _, x := range xs {
+		e.varint(uint64(x))
+	}
+	e.endMessage(field, start)
+}
+
+// startMessage starts a nested message and returns its start offset, which
+// must be passed to endMessage.
+func (e *protoEncoder) startMessage() int {
+	return len(e.data)
+}
+
+// endMessage completes the nested message starting at the given offset by
+// prefixing it with the key of the field and its length.
+func (e *protoEncoder) endMessage(field, start int) {
+	msgLen := len(e.data) - start
+	var prefix protoEncoder
+	prefix.data = make([]byte, 0, 20)
+	prefix.key(field, protoWireBytes)
+	prefix.varint(uint64(msgLen))
+	e.data = append(e.data, prefix.data...)
+	copy(e.data[start+len(prefix.data):], e.data[start:start+msgLen])
+	copy(e.data[start:], prefix.data)
+}
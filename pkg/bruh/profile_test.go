@@ -0,0 +1,134 @@
+package bruh_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+// protoField is a field of a decoded protocol buffer message.
+type protoField struct {
+	num   int
+	value uint64
+	data  []byte
+}
+
+// decodeProto decodes the fields of a protocol buffer message. Only the wire
+// types used by pprof profiles are supported.
+func decodeProto(t *testing.T, data []byte) []protoField {
+	t.Helper()
+	var fields []protoField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("invalid key")
+		}
+		data = data[n:]
+		field := protoField{num: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			field.value, n = binary.Uvarint(data)
+			if n <= 0 {
+				t.Fatalf("invalid varint")
+			}
+			data = data[n:]
+		case 2:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || int(l) > len(data[n:]) {
+				t.Fatalf("invalid length")
+			}
+			field.data = data[n : n+int(l)]
+			data = data[n+int(l):]
+		default:
+			t.Fatalf("unsupported wire type %d", key&7)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// decodePacked decodes a packed repeated varint field.
+func decodePacked(data []byte) []uint64 {
+	var values []uint64
+	for len(data) > 0 {
+		v, n := binary.Uvarint(data)
+		values = append(values, v)
+		data = data[n:]
+	}
+	return values
+}
+
+//go:noinline
+func createProfiledErrors() {
+	for i := range 3 {
+		err := bruh.New("profiled")
+		_ = bruh.Wrapf(err, "wrapped %d", i)
+	}
+}
+
+func TestErrorProfile(t *testing.T) {
+	assert := testutils.NewAssert(t)
+
+	profile := bruh.StartErrorProfile()
+	createProfiledErrors()
+	profile.Stop()
+	// not recorded anymore
+	createProfiledErrors()
+
+	var buf bytes.Buffer
+	n, err := profile.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(int64(buf.Len()), n)
+	zr, err := gzip.NewReader(&buf)
+	assert.NoError(err)
+	data, err := io.ReadAll(zr)
+	assert.NoError(err)
+
+	var (
+		strs        []string
+		sampleTypes int
+		samples     [][]uint64
+		locations   int
+		functions   int
+		mappings    int
+	)
+	for _, field := range decodeProto(t, data) {
+		switch field.num {
+		case 1:
+			sampleTypes++
+		case 2:
+			for _, sf := range decodeProto(t, field.data) {
+				if sf.num == 2 {
+					samples = append(samples, decodePacked(sf.data))
+				}
+			}
+		case 3:
+			mappings++
+		case 4:
+			locations++
+		case 5:
+			functions++
+		case 6:
+			strs = append(strs, string(field.data))
+		}
+	}
+	assert.Equal(2, sampleTypes)
+	assert.Equal(1, mappings)
+	assert.True(locations > 0)
+	assert.True(functions > 0)
+	assert.Equal("", strs[0])
+	assert.True(bytes.Contains(data, []byte("github.com/aisbergg/go-bruh/pkg/bruh_test.createProfiledErrors")))
+
+	// one sample for New and one for Wrapf
+	assert.Len(samples, 2)
+	for _, values := range samples {
+		assert.Len(values, 2)
+		assert.Equal(uint64(3), values[0])
+		assert.True(values[1] > 0)
+	}
+}
@@ -0,0 +1,503 @@
+package bruh
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ReportVersion is the version of the [Report] schema. It is incremented
+// whenever the serialized form of a report changes in an incompatible way.
+const ReportVersion = 1
+
+// Report is a serializable snapshot of an error chain. Unlike [UnpackedError]
+// and [Stack] obtained from an [Unpacker], a Report does not use any pooled
+// memory and can be retained, marshalled to JSON and sent to other processes.
+// Use [Report.Err] to turn it back into an error that can be formatted with any
+// [Formatter].
+type Report struct {
+	// Version is the version of the report schema, see [ReportVersion].
+	Version int `json:"version"`
+	// Errors are the errors of the chain in depth-first order, the same way as
+	// they are returned by [Unpacker.Unpack].
+	Errors []ReportElement `json:"errors"`
+	// Fingerprint is the fingerprint of the error chain with the default
+	// options, see [Fingerprint]. It is empty for reports created by
+	// [NewPCReport], because the fingerprint requires symbolized stacks.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Tags are the tags of the error chain (see package ctxerror).
+	Tags map[string]string `json:"tags,omitempty"`
+	// Context is the context of the error chain (see package ctxerror).
+	Context map[string]map[string]any `json:"context,omitempty"`
+	// Binary describes the binary that created the report. It is only set for
+	// reports created by [NewPCReport], which must be symbolized with the
+	// matching binary.
+	Binary *BinaryInfo `json:"binary,omitempty"`
+	// CreatedBy describes the goroutine that created the goroutine the error
+	// occurred in, e.g. for errors of goroutines started with [Go]. It is nil
+	// if unknown.
+	CreatedBy *ReportCreatedBy `json:"created_by,omitempty"`
+}
+
+// ReportElement is a single error of a [Report].
+type ReportElement struct {
+	// Message is the message of this error, without the messages of wrapped
+	// errors.
+	Message string `json:"message"`
+	// FullMessage is the full message of this error, including the messages of
+	// wrapped errors. It is only set if it cannot be reconstructed by joining
+	// the message with the messages of the wrapped errors.
+	FullMessage string `json:"full_message,omitempty"`
+	// Type is the type name of the error, e.g. `*bruh.Err`.
+	Type string `json:"type"`
+	// Kind is the kind of this error, not considering wrapped errors. For
+	// foreign errors, it is the kind determined by the registry, see
+	// [RegisterKind].
+	Kind Kind `json:"kind,omitempty"`
+	// Stack is the symbolized stack trace of this error.
+	Stack []ReportFrame `json:"stack,omitempty"`
+	// PCs are the raw program counters of this error, as returned by
+	// [runtime.Callers]. They are only set for reports created by
+	// [NewPCReport] and are replaced by Stack once the report is symbolized.
+	PCs []uintptr `json:"pcs,omitempty"`
+	// Parent is the index of the element that wraps this error. It is -1 for
+	// the root of the error tree.
+	Parent int `json:"parent"`
+	// Children are the indices of the elements that are wrapped by this error.
+	Children []int `json:"children,omitempty"`
+	// Goroutine describes the goroutine the error occurred in, if known. Its
+	// stack is not included. The creator of the goroutine is described by
+	// [Report.CreatedBy].
+	Goroutine *ReportGoroutine `json:"goroutine,omitempty"`
+	// Suppressed are the reports of the errors that were suppressed by this
+	// error, see [AddSuppressed].
+	Suppressed []*Report `json:"suppressed,omitempty"`
+}
+
+// ReportGoroutine is the goroutine an error of a [Report] occurred in.
+type ReportGoroutine struct {
+	// ID is the ID of the goroutine. It is zero if unknown.
+	ID int `json:"id,omitempty"`
+	// State is the state of the goroutine, e.g. `running`.
+	State string `json:"state,omitempty"`
+}
+
+// ReportCreatedBy describes the goroutine that created the goroutine an error
+// of a [Report] occurred in.
+type ReportCreatedBy struct {
+	// ID is the ID of the creating goroutine. It is zero if unknown.
+	ID int `json:"id,omitempty"`
+	// Stack is the stack of the creating goroutine at the time the goroutine
+	// was created. It consists of the frame of the `go` statement only, if the
+	// full stack is unknown.
+	Stack []ReportFrame `json:"stack"`
+}
+
+// ReportFrame is a stack frame of a [Report]. It is the serialized form of a
+// [StackFrame].
+type ReportFrame struct {
+	// Function is the name of the function.
+	Function string `json:"function"`
+	// File is the path of the file the function is defined in.
+	File string `json:"file"`
+	// Line is the line number of the call.
+	Line int `json:"line"`
+	// PC is the [StackFrame.ProgramCounter] of the frame.
+	PC uintptr `json:"pc,omitempty"`
+	// PC2 is the [StackFrame.ProgramCounter2] of the frame.
+	PC2 uintptr `json:"pc2,omitempty"`
+	// Offset is the [StackFrame.Offset] of the frame.
+	Offset uintptr `json:"offset,omitempty"`
+}
+
+// newReportFrames converts the stack into report frames. It returns nil for
+// an empty stack.
+func newReportFrames(stack Stack) []ReportFrame {
+	if len(stack) == 0 {
+		return nil
+	}
+	frames := make([]ReportFrame, len(stack))
+	for i := range stack {
+		s := &stack[i]
+		frames[i] = ReportFrame{
+			Function: s.Name,
+			File:     s.File,
+			Line:     s.Line,
+			PC:       s.ProgramCounter,
+			PC2:      s.ProgramCounter2,
+			Offset:   s.Offset,
+		}
+	}
+	return frames
+}
+
+// reportStack converts the report frames back into a stack. It returns nil
+// for empty frames.
+func reportStack(frames []ReportFrame) Stack {
+	if len(frames) == 0 {
+		return nil
+	}
+	stack := make(Stack, len(frames))
+	for i := range frames {
+		f := &frames[i]
+		stack[i] = StackFrame{
+			Name:            f.Function,
+			File:            f.File,
+			Line:            f.Line,
+			ProgramCounter:  f.PC,
+			ProgramCounter2: f.PC2,
+			Offset:          f.Offset,
+		}
+	}
+	return stack
+}
+
+// NewReport creates a [Report] from the given error. If err is nil, nil is
+// returned. The unpackAll parameter has the same meaning as in [StringFormat].
+//
+// Tags and context of package ctxerror are not included, because this package
+// does not know about them. Use ctxerror.NewReport to include them.
+func NewReport(err error, unpackAll ...bool) *Report {
+	return newReport(err, len(unpackAll) > 0 && unpackAll[0], false)
+}
+
+func newReport(err error, unpackAll, pcOnly bool) *Report {
+	if err == nil {
+		return nil
+	}
+	unpacker := newUnpacker(err, unpackAll)
+	unpacker.pcOnly = pcOnly
+	upkErr := unpacker.Unpack()
+	report := &Report{
+		Version: ReportVersion,
+		Errors:  make([]ReportElement, len(upkErr)),
+	}
+	if !pcOnly {
+		report.Fingerprint = Fingerprint(err)
+	}
+	if gr := unpacker.Goroutine(); gr != nil {
+		if stack := gr.creatorStack(); len(stack) > 0 {
+			report.CreatedBy = &ReportCreatedBy{ID: gr.CreatorID, Stack: newReportFrames(stack)}
+		}
+	}
+	// copy the elements, because the unpacked error is recycled
+	for i := range upkErr {
+		upkElm := &upkErr[i]
+		elm := &report.Errors[i]
+		elm.Message = upkElm.Msg
+		elm.Type = typeName(upkElm.Err)
+		elm.Kind = elementKind(upkElm.Err)
+		elm.Stack = newReportFrames(upkElm.Stack)
+		if gr := upkElm.Goroutine; gr != nil {
+			elm.Goroutine = &ReportGoroutine{ID: gr.ID, State: gr.State}
+		}
+		if cerr, ok := upkElm.Err.(callerser); ok && pcOnly {
+			elm.PCs = append([]uintptr(nil), cerr.Callers()...)
+		}
+		elm.Parent = upkElm.Parent
+		if len(upkElm.Children) > 0 {
+			elm.Children = append([]int(nil), upkElm.Children...)
+		}
+		if len(upkElm.Suppressed) > 0 {
+			elm.Suppressed = make([]*Report, len(upkElm.Suppressed))
+			for j, serr := range upkElm.Suppressed {
+				elm.Suppressed[j] = newReport(serr, unpackAll, pcOnly)
+			}
+		}
+	}
+	// keep the full messages that cannot be reconstructed
+	for i := len(upkErr) - 1; i >= 0; i-- {
+		fullMsg := upkErr[i].Err.Error()
+		if reportElements(report.Errors).fullMessage(i) != fullMsg {
+			report.Errors[i].FullMessage = fullMsg
+		}
+	}
+	disposeUnpacker(unpacker)
+	return report
+}
+
+// UnmarshalReport parses a JSON encoded [Report]. Use [Report.Err] to turn it
+// into an error. Reports of a newer version than [ReportVersion] are rejected
+// with an error, because they may be incompatible.
+func UnmarshalReport(data []byte) (*Report, error) {
+	report := &Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, Wrap(err, "unmarshalling error report")
+	}
+	// reports of newer versions may be incompatible and are not guessed at
+	if report.Version > ReportVersion {
+		return nil, Errorf("unmarshalling error report: unsupported version %d, at most version %d is supported",
+			report.Version, ReportVersion)
+	}
+	return report, nil
+}
+
+// Err turns the report into an error. The returned error is a [*RemoteErr] or,
+// if the root error wraps multiple errors, an error implementing
+// `Unwrap() []error` that behaves like a [*RemoteErr]. Suppressed errors are
+// attached again, see [Suppressed]. It formats through any [Formatter] as if it
+// were the original error. If the report contains no errors, nil is returned.
+//
+// The identity of the original errors is lost, therefore [errors.Is] and
+// [errors.As] cannot match the original errors or types.
+func (r *Report) Err() error {
+	if r == nil || len(r.Errors) == 0 {
+		return nil
+	}
+	errs := make([]error, len(r.Errors))
+	remoteErrs := make([]*RemoteErr, len(r.Errors))
+	rootIdx := 0
+	for i := len(r.Errors) - 1; i >= 0; i-- {
+		elm := &r.Errors[i]
+		rerr := &RemoteErr{
+			msg:     elm.Message,
+			fullMsg: elm.FullMessage,
+			typ:     elm.Type,
+			kind:    elm.Kind,
+			stack:   reportStack(elm.Stack),
+		}
+		if gr := elm.Goroutine; gr != nil {
+			rerr.goroutine = &Goroutine{ID: gr.ID, State: gr.State}
+		}
+		remoteErrs[i] = rerr
+		children := make([]error, 0, len(elm.Children))
+		for _, c := range elm.Children {
+			if c > i && c < len(errs) && errs[c] != nil {
+				children = append(children, errs[c])
+			}
+		}
+		switch len(children) {
+		case 0:
+			errs[i] = rerr
+		case 1:
+			rerr.err = children[0]
+			errs[i] = rerr
+		default:
+			errs[i] = &remoteJoinErr{RemoteErr: rerr, errs: children}
+		}
+		for _, sr := range elm.Suppressed {
+			AddSuppressed(&errs[i], sr.Err())
+		}
+		if elm.Parent < 0 {
+			rootIdx = i
+		}
+	}
+	remoteErrs[rootIdx].tags = r.Tags
+	remoteErrs[rootIdx].context = r.Context
+	if r.CreatedBy != nil && len(r.CreatedBy.Stack) > 0 {
+		r.restoreCreator(remoteErrs, rootIdx)
+	}
+	return errs[rootIdx]
+}
+
+// restoreCreator attaches the creating goroutine to the goroutine of the
+// deepest error of the first branch that knows its goroutine, which is the one
+// reported by [Unpacker.Goroutine]. If no error knows its goroutine, it is
+// attached to the root error.
+func (r *Report) restoreCreator(remoteErrs []*RemoteErr, rootIdx int) {
+	target := rootIdx
+	for i := rootIdx; ; i = r.Errors[i].Children[0] {
+		if remoteErrs[i].goroutine != nil {
+			target = i
+		}
+		if children := r.Errors[i].Children; len(children) == 0 || children[0] <= i || children[0] >= len(r.Errors) {
+			break
+		}
+	}
+	gr := remoteErrs[target].goroutine
+	if gr == nil {
+		gr = &Goroutine{}
+		remoteErrs[target].goroutine = gr
+	}
+	gr.CreatorID = r.CreatedBy.ID
+	gr.Creator = reportStack(r.CreatedBy.Stack)
+	gr.CreatedBy = &gr.Creator[0]
+}
+
+// reportElements is a list of report elements.
+type reportElements []ReportElement
+
+// fullMessage reconstructs the full message of the element at index i.
+func (r reportElements) fullMessage(i int) string {
+	elm := &r[i]
+	if elm.FullMessage != "" {
+		return elm.FullMessage
+	}
+	switch len(elm.Children) {
+	case 0:
+		return elm.Message
+	case 1:
+		childMsg := r.fullMessage(elm.Children[0])
+		switch {
+		case elm.Message == "":
+			return childMsg
+		case childMsg == "":
+			return elm.Message
+		default:
+			return elm.Message + ": " + childMsg
+		}
+	default:
+		var sb strings.Builder
+		if elm.Message != "" {
+			sb.WriteString(elm.Message)
+			sb.WriteString(": ")
+		}
+		for j, c := range elm.Children {
+			if j > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(r.fullMessage(c))
+		}
+		return sb.String()
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// RemoteErr is an error that was reconstructed from a [Report]. It carries the
+// messages, type names and already symbolized stack traces of the original
+// errors and can be formatted with any [Formatter].
+type RemoteErr struct { //nolint: errname
+	msg       string
+	fullMsg   string
+	typ       string
+	kind      Kind
+	stack     Stack
+	goroutine *Goroutine
+	err       error
+	tags      map[string]string
+	context   map[string]map[string]any
+}
+
+// Message returns the single, unformatted message of this error, without the
+// messages of wrapped errors.
+func (e *RemoteErr) Message() string {
+	return e.msg
+}
+
+// Error returns the formatted error message including the messages of wrapped
+// errors.
+func (e *RemoteErr) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.fullMsg != "" {
+		return e.fullMsg
+	}
+	if e.err == nil {
+		return e.msg
+	}
+	msg := e.err.Error()
+	switch {
+	case e.msg == "":
+		return msg
+	case msg == "":
+		return e.msg
+	default:
+		return e.msg + ": " + msg
+	}
+}
+
+// TypeName returns the type name of the original error, e.g. `*bruh.Err`.
+func (e *RemoteErr) TypeName() string {
+	return e.typ
+}
+
+// Kind returns the kind of the original error, not considering wrapped errors.
+func (e *RemoteErr) Kind() Kind {
+	return e.kind
+}
+
+// Frames returns the symbolized stack trace of the original error.
+func (e *RemoteErr) Frames() Stack {
+	return e.stack
+}
+
+// Goroutine returns the goroutine the original error occurred in. It is nil if
+// unknown.
+func (e *RemoteErr) Goroutine() *Goroutine {
+	return e.goroutine
+}
+
+// Unwrap returns the wrapped remote error.
+func (e *RemoteErr) Unwrap() error {
+	return e.err
+}
+
+// Stack returns a combined stack trace of all errors in the chain.
+func (e *RemoteErr) Stack() Stack {
+	stack := *newChainStack()
+	stack = stack[:combinedStack(e, stack)]
+	return stack
+}
+
+// StackFrames is an alias for [*RemoteErr.Stack].
+func (e *RemoteErr) StackFrames() Stack {
+	return e.Stack()
+}
+
+// Tags returns the tags of the original error chain. It makes the tags
+// available to ctxerror.GetTags.
+func (e *RemoteErr) Tags() map[string]string {
+	return e.tags
+}
+
+// Context returns the context of the original error chain. It makes the
+// context available to ctxerror.GetContext.
+func (e *RemoteErr) Context() map[string]map[string]any {
+	return e.context
+}
+
+// Format implements the fmt.Formatter interface. See [Err.Format] for details.
+func (e *RemoteErr) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+// remoteJoinErr is a [RemoteErr] that wraps multiple remote errors.
+type remoteJoinErr struct { //nolint: errname
+	*RemoteErr
+	errs []error
+}
+
+// Error returns the formatted error message including the messages of wrapped
+// errors.
+func (e *remoteJoinErr) Error() string {
+	if e.fullMsg != "" {
+		return e.fullMsg
+	}
+	var sb strings.Builder
+	if e.msg != "" {
+		sb.WriteString(e.msg)
+		sb.WriteString(": ")
+	}
+	for i, err := range e.errs {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap returns the wrapped remote errors.
+func (e *remoteJoinErr) Unwrap() []error {
+	return e.errs
+}
+
+// Stack returns a combined stack trace of the error and the first branch of
+// the wrapped errors.
+func (e *remoteJoinErr) Stack() Stack {
+	stack := *newChainStack()
+	stack = stack[:combinedStack(e, stack)]
+	return stack
+}
+
+// StackFrames is an alias for [*remoteJoinErr.Stack].
+func (e *remoteJoinErr) StackFrames() Stack {
+	return e.Stack()
+}
+
+// Format implements the fmt.Formatter interface. See [Err.Format] for details.
+func (e *remoteJoinErr) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
@@ -0,0 +1,168 @@
+package bruh
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aisbergg/go-bruh/internal/buildid"
+)
+
+// BinaryInfo identifies the binary that created a PC-only [Report]. It is
+// required to symbolize the program counters of the report with the matching
+// binary.
+type BinaryInfo struct {
+	// BuildID is the Go build ID of the binary. It is empty if it could not be
+	// determined, e.g. on platforms that don't use ELF binaries.
+	BuildID string `json:"build_id,omitempty"`
+	// LoadAddress is the address at which the first segment of the binary was
+	// mapped into memory. For position-independent executables, it is used to
+	// translate the program counters into addresses of the binary file. It is
+	// zero if it could not be determined.
+	LoadAddress uint64 `json:"load_address,omitempty"`
+	// GOOS is the operating system the binary was built for.
+	GOOS string `json:"goos"`
+	// GOARCH is the architecture the binary was built for.
+	GOARCH string `json:"goarch"`
+}
+
+var (
+	currentBinaryInfoOnce sync.Once
+	currentBinaryInfo     BinaryInfo
+)
+
+// getBinaryInfo returns the [BinaryInfo] of the running binary. It is
+// determined only once and then cached.
+func getBinaryInfo() BinaryInfo {
+	currentBinaryInfoOnce.Do(func() {
+		currentBinaryInfo = BinaryInfo{GOOS: runtime.GOOS, GOARCH: runtime.GOARCH}
+		exe, err := os.Executable()
+		if err != nil {
+			return
+		}
+		if f, err := elf.Open(exe); err == nil {
+			currentBinaryInfo.BuildID = buildid.FromELF(f)
+			_ = f.Close()
+		}
+		currentBinaryInfo.LoadAddress = loadAddress(exe)
+	})
+	return currentBinaryInfo
+}
+
+// loadAddress returns the start address of the first memory mapping of the
+// executable. It reads the mappings from `/proc/self/maps`, therefore it only
+// works on Linux. On other platforms, zero is returned.
+func loadAddress(exe string) uint64 {
+	data, err := os.ReadFile("/proc/self/maps")
+	if err != nil {
+		return 0
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// format: address perms offset dev inode pathname
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[5] != exe || strings.Trim(fields[2], "0") != "" {
+			continue
+		}
+		start, _, _ := strings.Cut(fields[0], "-")
+		addr, err := strconv.ParseUint(start, 16, 64)
+		if err != nil {
+			return 0
+		}
+		return addr
+	}
+	return 0
+}
+
+// NewPCReport creates a [Report] from the given error, just like [NewReport],
+// but without resolving the program counters of the stack traces into
+// function names, files and lines. Instead, the raw program counters are
+// stored in [ReportElement.PCs] along with information about the running
+// binary in [Report.Binary]. This makes the creation of reports considerably
+// cheaper, which is useful for services that report a high volume of errors.
+//
+// The report can be symbolized later, e.g. by a separate process that has
+// access to the matching unstripped binary, using [Report.Symbolize]. Until
+// then, the report has no stack traces.
+func NewPCReport(err error, unpackAll ...bool) *Report {
+	report := newReport(err, len(unpackAll) > 0 && unpackAll[0], true)
+	if report == nil {
+		return nil
+	}
+	binaryInfo := getBinaryInfo()
+	report.Binary = &binaryInfo
+	return report
+}
+
+// Symbolizer resolves program counters into stack frames.
+type Symbolizer interface {
+	// Frames returns the stack frames for the given program counter, as
+	// returned by [runtime.Callers]. A program counter can resolve to more
+	// than one frame, if calls were inlined. In that case the innermost frame
+	// comes first. If the program counter cannot be resolved, nil is returned.
+	Frames(pc uintptr) []StackFrame
+}
+
+// Symbolize resolves the program counters of a report created by
+// [NewPCReport] using the given [Symbolizer]. The resolved frames are
+// stored in [ReportElement.Stack] and the program counters are removed. The
+// frames are filtered the same way as for errors that are symbolized
+// in-process, so that the symbolized report formats exactly like a report
+// created by [NewReport].
+func (r *Report) Symbolize(s Symbolizer) {
+	if r == nil {
+		return
+	}
+	for i := range r.Errors {
+		elm := &r.Errors[i]
+		for _, sr := range elm.Suppressed {
+			sr.Symbolize(s)
+		}
+		if len(elm.PCs) == 0 {
+			continue
+		}
+		elm.Stack = newReportFrames(symbolizePCs(s, elm.PCs))
+		elm.PCs = nil
+	}
+}
+
+// symbolizePCs resolves the program counters into a stack. It follows the
+// same rules as [stackPC.toStack].
+func symbolizePCs(s Symbolizer, pcs []uintptr) Stack {
+	stack := make(Stack, 0, len(pcs))
+	for _, pc := range pcs {
+		frames := s.Frames(pc)
+		if len(frames) == 0 {
+			frames = []StackFrame{{Name: "?", ProgramCounter: pc}}
+		}
+		for _, frame := range frames {
+			// discard stack for globally defined errors
+			if isGloballyDefinedError(frame.Name) {
+				return nil
+			}
+			// exclude runtime calls
+			name, ok := stackFrameName(frame.Name, frame.File)
+			if !ok {
+				continue
+			}
+			frame.Name = name
+			if len(stack) == cap(stack) {
+				return stack
+			}
+			stack = append(stack, frame)
+		}
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack
+}
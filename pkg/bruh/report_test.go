@@ -0,0 +1,211 @@
+package bruh_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+// suppressedError returns a wrapped error that suppresses a wrapped and a
+// joined error.
+func suppressedError() error {
+	err := bruh.New("primary")
+	bruh.AddSuppressed(&err, bruh.Wrap(bruh.New("closing"), "cleanup"), joinedError())
+	return bruh.Wrap(err, "context")
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	formatters := map[string]bruh.Formatter{
+		"Bruh":         bruh.BruhFormatter,
+		"BruhStacked":  bruh.BruhStackedFormatter,
+		"GoPanic":      bruh.GoPanicFormatter,
+		"JavaStack":    bruh.JavaStackTraceFormatter,
+		"PythonTrace":  bruh.PythonTracebackFormatter,
+		"DefaultStack": nil,
+	}
+
+	assertRoundTrip := func(name string, err error) {
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			data, jerr := json.Marshal(bruh.NewReport(err))
+			assert.NoError(jerr)
+			report, jerr := bruh.UnmarshalReport(data)
+			assert.NoError(jerr)
+			assert.Equal(bruh.ReportVersion, report.Version)
+			remote := report.Err()
+			assert.Equal(err.Error(), remote.Error())
+			assert.Equal(bruh.Message(err), bruh.Message(remote))
+			for fname, f := range formatters {
+				if f == nil {
+					assert.Equal(bruh.String(err), bruh.String(remote), fname)
+					continue
+				}
+				assert.Equal(bruh.StringFormat(err, f), bruh.StringFormat(remote, f), fname)
+			}
+		})
+	}
+
+	assertRoundTrip("SingleRoot", singleRootError())
+	assertRoundTrip("EmptyMessage", emptyMessageError())
+	assertRoundTrip("Wrapped", wrappedError3())
+	assertRoundTrip("WrappedEmptyMessage", wrappedEmptyMessageError())
+	assertRoundTrip("External", externalError())
+	assertRoundTrip("ExternallyWrapped", externallyWrappedError())
+	assertRoundTrip("WrappedExternal", wrappedExternalError())
+	assertRoundTrip("WrappedExternalInterleaved", wrappedExternalInterleavedError())
+	assertRoundTrip("ExternallyWrappedNil", externallyWrappedNilError())
+	assertRoundTrip("WrappedGlobal", wrappedGlobalError())
+	assertRoundTrip("Joined", joinedError())
+	assertRoundTrip("Suppressed", suppressedError())
+
+	t.Run("SuppressedErrors", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := suppressedError()
+		data, jerr := json.Marshal(bruh.NewReport(err))
+		assert.NoError(jerr)
+		report, jerr := bruh.UnmarshalReport(data)
+		assert.NoError(jerr)
+		assert.Len(report.Errors[1].Suppressed, 2)
+		suppressed := bruh.Suppressed(report.Err())
+		assert.Len(suppressed, 2)
+		for i, serr := range bruh.Suppressed(err) {
+			assert.Equal(serr.Error(), suppressed[i].Error())
+		}
+	})
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.True(bruh.NewReport(nil) == nil)
+		assert.Nil((*bruh.Report)(nil).Err())
+		assert.Nil((&bruh.Report{}).Err())
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		_, err := bruh.UnmarshalReport([]byte("{"))
+		assert.Error(err)
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		_, err := bruh.UnmarshalReport([]byte(`{"version":2,"errors":[{"message":"failed"}]}`))
+		assert.Error(err)
+		report, err := bruh.UnmarshalReport([]byte(`{"version":1,"errors":[{"message":"failed"}]}`))
+		assert.NoError(err)
+		assert.Equal(bruh.ReportVersion, report.Version)
+	})
+
+	t.Run("Elements", func(t *testing.T) {
+		report := bruh.NewReport(wrappedExternalError())
+		assert.Len(report.Errors, 2)
+		assert.Equal("wrapped 1", report.Errors[0].Message)
+		assert.Equal("*bruh.Err", report.Errors[0].Type)
+		assert.Equal(-1, report.Errors[0].Parent)
+		assert.Equal([]int{1}, report.Errors[0].Children)
+		assert.Equal("external error", report.Errors[1].Message)
+		assert.Equal("*errors.errorString", report.Errors[1].Type)
+		assert.Equal(0, report.Errors[1].Parent)
+		assert.Len(report.Errors[1].Stack, 0)
+	})
+
+	t.Run("WireFormat", func(t *testing.T) {
+		report := bruh.NewReport(singleRootError())
+		data, err := json.Marshal(report.Errors[0].Stack[0])
+		assert.NoError(err)
+		var frame map[string]any
+		assert.NoError(json.Unmarshal(data, &frame))
+		assert.Equal(report.Errors[0].Stack[0].Function, frame["function"])
+		assert.NotNil(frame["file"])
+		assert.NotNil(frame["line"])
+
+		// StackFrame keeps its own field names
+		data, err = json.Marshal(bruh.StackFrame{Name: "fn"})
+		assert.NoError(err)
+		assert.NoError(json.Unmarshal(data, &frame))
+		assert.Equal("fn", frame["Name"])
+	})
+
+	t.Run("RemoteErr", func(t *testing.T) {
+		remote := bruh.NewReport(wrappedExternalError()).Err()
+		rerr, ok := remote.(*bruh.RemoteErr)
+		assert.True(ok, "expected *bruh.RemoteErr")
+		assert.Equal("wrapped 1", rerr.Message())
+		assert.Equal("*bruh.Err", rerr.TypeName())
+		assert.True(len(rerr.Frames()) > 0, "expected frames")
+		assert.Equal("external error", bruh.Unwrap(remote).Error())
+		assert.Len(bruh.UnwrapAll(bruh.NewReport(joinedError()).Err()), 1)
+	})
+
+	t.Run("WrapRemoteErr", func(t *testing.T) {
+		remote := bruh.NewReport(wrappedError1()).Err()
+		err := bruh.Wrap(remote, "local")
+		assert.Equal("local: wrapped 1: root error", err.Error())
+		assert.Equal("local: wrapped 1: root error", bruh.Message(err))
+		assert.Equal("wrapped 1: root error", bruh.MessageLastN(err, 2))
+	})
+}
+
+// runtimeSymbolizer resolves program counters of the running binary.
+type runtimeSymbolizer struct{}
+
+func (runtimeSymbolizer) Frames(pc uintptr) []bruh.StackFrame {
+	var stack []bruh.StackFrame
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, bruh.StackFrame{
+			Name:            frame.Function,
+			File:            frame.File,
+			Line:            frame.Line,
+			ProgramCounter:  pc,
+			ProgramCounter2: frame.PC,
+		})
+		if !more {
+			return stack
+		}
+	}
+}
+
+func TestPCReport(t *testing.T) {
+	t.Parallel()
+
+	assertPCReport := func(name string, err error) {
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			report := bruh.NewPCReport(err)
+			assert.NotNil(report.Binary)
+			assert.Equal(runtime.GOOS, report.Binary.GOOS)
+			assert.Equal(runtime.GOARCH, report.Binary.GOARCH)
+			for i := range report.Errors {
+				assert.Len(report.Errors[i].Stack, 0)
+			}
+			report.Symbolize(runtimeSymbolizer{})
+			for i := range report.Errors {
+				assert.Len(report.Errors[i].PCs, 0)
+			}
+			assert.Equal(
+				bruh.StringFormat(err, bruh.BruhStackedFormatter),
+				bruh.StringFormat(report.Err(), bruh.BruhStackedFormatter),
+			)
+		})
+	}
+
+	assertPCReport("SingleRoot", singleRootError())
+	assertPCReport("Wrapped", wrappedError3())
+	assertPCReport("WrappedExternalInterleaved", wrappedExternalInterleavedError())
+	assertPCReport("WrappedGlobal", wrappedGlobalError())
+	assertPCReport("Joined", joinedError())
+	assertPCReport("Suppressed", suppressedError())
+
+	t.Run("Nil", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		assert.True(bruh.NewPCReport(nil) == nil)
+	})
+}
@@ -13,7 +13,7 @@ import (
 // creating stack traces. It provides an upper bound to prevent excessive memory
 // usage when serializing long error chains. If you require more stack frames,
 // simply increase MaxChainStackDepth.
-var MaxChainStackDepth = MaxErrorStackDepth * 6
+var MaxChainStackDepth = DefaultErrorStackDepth * 6
 
 // StackFrame stores a frame's runtime information in a human readable format.
 type StackFrame struct {
@@ -35,6 +35,20 @@ type StackFrame struct {
 	// ProgramCounter instead. ProgramCounter appears to offer greater
 	// reliability in conjunction with [runtime.CallersFrames].
 	ProgramCounter2 uintptr
+	// Offset is the offset of the return address from the entry of the
+	// function, as printed in the `+0x` suffix of Go's panic output. It is zero
+	// for inlined calls, which have no entry of their own.
+	Offset uintptr
+}
+
+// sameAs reports whether the two frames refer to the same call site. Frames are
+// compared by their program counters if both have one, otherwise by their
+// symbolic information.
+func (f *StackFrame) sameAs(other *StackFrame) bool {
+	if f.ProgramCounter2 != 0 && other.ProgramCounter2 != 0 {
+		return f.ProgramCounter2 == other.ProgramCounter2
+	}
+	return f.Line == other.Line && f.Name == other.Name && f.File == other.File
 }
 
 // Stack is an array of stack frames stored in a human readable format.
@@ -72,12 +86,12 @@ func (s Stack) RelativeTo(other Stack) Stack {
 	// find first common index in case the captured stack got truncated when its
 	// size exceeded MaxStackDepth
 	for othIdx >= 0 && curIdx >= 0 &&
-		other[othIdx].ProgramCounter2 != s[curIdx].ProgramCounter2 {
+		!other[othIdx].sameAs(&s[curIdx]) {
 		othIdx--
 	}
 	// find last common index
 	for othIdx >= 0 && curIdx >= 0 &&
-		other[othIdx].ProgramCounter2 == s[curIdx].ProgramCounter2 {
+		other[othIdx].sameAs(&s[curIdx]) {
 		othIdx--
 		curIdx--
 	}
@@ -236,11 +250,31 @@ func (s stackPC) relativeTo(other stackPC) stackPC {
 }
 
 // toStack fills the slice with information details about the program counters.
-// It returns the number of entries written to stack.
+// It returns the number of entries written to stack. If stack interning is
+// enabled, the symbolized stack is cached, see [EnableStackInterning].
 func (s stackPC) toStack(stack Stack) int {
 	if len(s) == 0 {
 		return 0
 	}
+	if in := stackInterning.Load(); in != nil {
+		return in.toStack(s, stack)
+	}
+	return s.symbolize(stack)
+}
+
+// symbolize fills the slice with information details about the program
+// counters. It returns the number of entries written to stack. The symbolic
+// information of the program counters is cached, see [SetSymbolCacheSize].
+func (s stackPC) symbolize(stack Stack) int {
+	if cache := loadSymbolCache(); cache != nil {
+		return cache.toStack(s, stack)
+	}
+	return s.symbolizeUncached(stack)
+}
+
+// symbolizeUncached behaves like [stackPC.symbolize], but resolves the program
+// counters without using the cache.
+func (s stackPC) symbolizeUncached(stack Stack) int {
 	frames := allocFrames(s)
 	var i int
 	// we don't want to exceed the number of allocated stack frames
@@ -252,14 +286,15 @@ func (s stackPC) toStack(stack Stack) int {
 			return 0
 		}
 		// exclude runtime calls
-		if strings.Contains(frame.File, "runtime/") {
+		name, ok := stackFrameName(frame.Function, frame.File)
+		if !ok {
 			if !more {
 				break
 			}
 			continue
 		}
 		stack[i] = StackFrame{
-			Name:            frame.Function,
+			Name:            name,
 			File:            frame.File,
 			Line:            frame.Line,
 			ProgramCounter2: frame.PC,
@@ -270,6 +305,10 @@ func (s stackPC) toStack(stack Stack) int {
 			// without the reduction.
 			ProgramCounter: s[i],
 		}
+		if frame.Func != nil && frame.PC >= frame.Entry {
+			// CallersFrames reduced the return address by 1, see above
+			stack[i].Offset = frame.PC + 1 - frame.Entry
+		}
 		i++
 		if !more {
 			break
@@ -284,3 +323,53 @@ func (s stackPC) toStack(stack Stack) int {
 func isGloballyDefinedError(f string) bool {
 	return strings.HasPrefix(f, "runtime.doInit") || strings.HasSuffix(f, ".init")
 }
+
+// PanicFrameName is the name of the stack frame that marks the site of a panic.
+// It is the frame of the runtime function that starts the panic, named the
+// same way as in Go's panic output. The frame below it is the one that
+// panicked.
+const PanicFrameName = "panic"
+
+// stackFrameName returns the name under which the function appears in stacks
+// and whether it appears at all. Calls of the runtime are excluded, except for
+// the start of a panic, which marks the site of the panic.
+func stackFrameName(function, file string) (string, bool) {
+	isRuntime := strings.Contains(file, "runtime/")
+	if function == "runtime.gopanic" || (function == PanicFrameName && isRuntime) {
+		return PanicFrameName, true
+	}
+	if isRuntime || isGoroutineLauncher(function) || isCompilerWrapper(function, file) {
+		return "", false
+	}
+	return function, true
+}
+
+// isCompilerWrapper returns true if the function is a wrapper generated by the
+// compiler, e.g. for method values or go and defer statements. Like
+// [runtime.Callers], which omits them in general, they are excluded from
+// stacks. They are only recorded, if the stack is captured by walking the
+// frame pointers.
+func isCompilerWrapper(function, file string) bool {
+	return file == "<autogenerated>" ||
+		strings.Contains(function, ".gowrap") ||
+		strings.Contains(function, ".deferwrap")
+}
+
+// goroutineLauncherPrefix is the common prefix of the functions that run the
+// goroutines started by [Go] and [GoWaitGroup].
+const goroutineLauncherPrefix = "github.com/aisbergg/go-bruh/pkg/bruh."
+
+// isGoroutineLauncher returns true if the function is part of running a
+// goroutine started by [Go] or [GoWaitGroup]. Like the goroutine entry
+// functions of the runtime, they are excluded from stacks.
+func isGoroutineLauncher(function string) bool {
+	name, ok := strings.CutPrefix(function, goroutineLauncherPrefix)
+	if !ok {
+		return false
+	}
+	switch name {
+	case "Go.func1", "GoWaitGroup.func1", "(*goroutineErr).run":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,329 @@
+package bruh
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultErrorStackDepth is the default maximum number of stack frames that are
+// captured per error. If a function call stack exceeds this depth, the excess
+// frames are truncated. This is generally not an issue, as the library merges
+// stack traces across the error chain during serialization. To ensure full
+// stack trace reconstruction, wrap errors from deeply nested calls to maintain
+// stack frame overlap. The depth can be changed at runtime with
+// [SetStackCapturePolicy].
+const DefaultErrorStackDepth = 24
+
+// MaxErrorStackDepth is the default maximum number of stack frames that are
+// captured per error.
+//
+// Deprecated: The depth is configured at runtime with [SetStackCapturePolicy].
+// MaxErrorStackDepth is the same as [DefaultErrorStackDepth].
+const MaxErrorStackDepth = DefaultErrorStackDepth
+
+// maxStackCaptureDepth is the upper limit of [StackCapturePolicy.Depth].
+const maxStackCaptureDepth = 128
+
+// StackCaptureMode defines how the stack is captured when an error is created.
+type StackCaptureMode uint8
+
+const (
+	// StackCaptureFull captures the stack of every error, up to
+	// [StackCapturePolicy.Depth] frames. This is the default.
+	StackCaptureFull StackCaptureMode = iota
+	// StackCaptureCaller captures only the frame of the function that created
+	// the error.
+	StackCaptureCaller
+	// StackCaptureSampled captures the full stack of 1 in
+	// [StackCapturePolicy.SampleRate] errors per call site, starting with the
+	// first one. The other errors capture only the frame of the function that
+	// created them.
+	StackCaptureSampled
+	// StackCaptureOff captures no stack at all.
+	StackCaptureOff
+)
+
+// StackCaptureBackend defines the mechanism by which the stack is captured.
+type StackCaptureBackend uint8
+
+const (
+	// StackCaptureCallers captures the stack with [runtime.Callers]. This is
+	// the default.
+	StackCaptureCallers StackCaptureBackend = iota
+	// StackCaptureFramePointers captures the stack by walking the frame
+	// pointers, which is considerably faster than [runtime.Callers]. It is
+	// supported on amd64 and arm64 and falls back to [runtime.Callers] on
+	// other architectures or if the symbol cache is disabled (see
+	// [SetSymbolCacheSize]). Unlike [runtime.Callers], it doesn't record the
+	// calls of inlined functions, which are restored when the stack is
+	// symbolized, and it records the calls of compiler generated wrapper
+	// functions, which are omitted when the stack is symbolized.
+	StackCaptureFramePointers
+	// StackCaptureCrossCheck captures the stack with both [runtime.Callers]
+	// and the frame pointers and panics if the stacks differ. It is intended
+	// for tests that verify the frame pointer backend for a code base.
+	StackCaptureCrossCheck
+)
+
+// StackCapturePolicy defines how the stacks of errors are captured. Capturing
+// the stack is the most expensive part of creating an error. A less
+// comprehensive policy reduces the cost, e.g. for hot paths, where errors are
+// expected and handled. The [Unpacker] and formatters handle errors with a
+// reduced or missing stack gracefully.
+type StackCapturePolicy struct {
+	// Mode defines how the stack is captured.
+	Mode StackCaptureMode
+	// Depth is the maximum number of stack frames that are captured per error.
+	// Defaults to [DefaultErrorStackDepth] and is limited to 128.
+	Depth int
+	// SampleRate is the N in the 1 in N errors per call site whose full stack
+	// is captured in [StackCaptureSampled] mode. Values below 2 capture the
+	// full stack of every error.
+	SampleRate int
+	// Backend defines the mechanism by which the stack is captured.
+	Backend StackCaptureBackend
+}
+
+// normalized returns the policy with the defaults applied.
+func (p StackCapturePolicy) normalized() StackCapturePolicy {
+	if p.Depth <= 0 {
+		p.Depth = DefaultErrorStackDepth
+	}
+	p.Depth = min(p.Depth, maxStackCaptureDepth)
+	if p.Mode == StackCaptureSampled && p.SampleRate < 2 {
+		p.Mode = StackCaptureFull
+	}
+	return p
+}
+
+// packageStackCapturePolicy is a policy that applies to the errors created in
+// functions with the given name prefix.
+type packageStackCapturePolicy struct {
+	prefix string
+	policy StackCapturePolicy
+}
+
+// stackCaptureConfig is the immutable configuration of the stack capture. It is
+// replaced as a whole when a policy is changed.
+type stackCaptureConfig struct {
+	policy   StackCapturePolicy
+	packages []packageStackCapturePolicy
+	// perCallSite is true if the policy must be determined per call site,
+	// because there are package policies or errors are sampled.
+	perCallSite bool
+	// callSites caches the policies and sample counters of the call sites.
+	callSites sync.Map // map[uintptr]*stackCaptureCallSite
+}
+
+// stackCaptureCallSite is the state of a call site that creates errors.
+type stackCaptureCallSite struct {
+	policy StackCapturePolicy
+	count  atomic.Uint64
+}
+
+var (
+	stackCaptureMu     sync.Mutex
+	stackCapture       atomic.Pointer[stackCaptureConfig]
+	stackCapturePolicy = StackCapturePolicy{}.normalized()
+	stackCapturePkgs   []packageStackCapturePolicy
+	// defaultStackCaptureConfig is used until a policy is set. Unlike an init
+	// function, it is also available to errors created during the
+	// initialization of the package variables.
+	defaultStackCaptureConfig = &stackCaptureConfig{policy: StackCapturePolicy{}.normalized()}
+)
+
+// loadStackCaptureConfig returns the active stack capture configuration.
+func loadStackCaptureConfig() *stackCaptureConfig {
+	if cfg := stackCapture.Load(); cfg != nil {
+		return cfg
+	}
+	return defaultStackCaptureConfig
+}
+
+// SetStackCapturePolicy sets the process-wide policy by which the stacks of
+// errors are captured. It applies to all errors that are not covered by a
+// package policy, see [SetPackageStackCapturePolicy].
+//
+// Example usage:
+//
+//	// capture deeper stacks
+//	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Depth: 48})
+//
+//	// capture the full stack of only 1 in 100 errors per call site
+//	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{
+//	    Mode:       bruh.StackCaptureSampled,
+//	    SampleRate: 100,
+//	})
+func SetStackCapturePolicy(policy StackCapturePolicy) {
+	stackCaptureMu.Lock()
+	defer stackCaptureMu.Unlock()
+	stackCapturePolicy = policy.normalized()
+	storeStackCaptureConfig()
+}
+
+// SetPackageStackCapturePolicy sets the policy by which the stacks of errors
+// are captured, that are created in functions whose fully qualified name
+// starts with the given prefix, e.g. `github.com/org/project/internal/cache`.
+// If multiple prefixes match, the longest one wins. Setting the policy of a
+// prefix again replaces the previous one.
+func SetPackageStackCapturePolicy(prefix string, policy StackCapturePolicy) {
+	stackCaptureMu.Lock()
+	defer stackCaptureMu.Unlock()
+	pkgs := make([]packageStackCapturePolicy, 0, len(stackCapturePkgs)+1)
+	for _, pkg := range stackCapturePkgs {
+		if pkg.prefix != prefix {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	pkgs = append(pkgs, packageStackCapturePolicy{prefix: prefix, policy: policy.normalized()})
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		return len(pkgs[i].prefix) > len(pkgs[j].prefix)
+	})
+	stackCapturePkgs = pkgs
+	storeStackCaptureConfig()
+}
+
+// ResetStackCapturePolicy restores the default policy and removes all package
+// policies.
+func ResetStackCapturePolicy() {
+	stackCaptureMu.Lock()
+	defer stackCaptureMu.Unlock()
+	stackCapturePolicy = StackCapturePolicy{}.normalized()
+	stackCapturePkgs = nil
+	storeStackCaptureConfig()
+}
+
+// storeStackCaptureConfig activates the current policies. stackCaptureMu must
+// be held.
+func storeStackCaptureConfig() {
+	cfg := &stackCaptureConfig{
+		policy:   stackCapturePolicy,
+		packages: stackCapturePkgs,
+	}
+	cfg.perCallSite = len(cfg.packages) > 0 || cfg.policy.Mode == StackCaptureSampled
+	stackCapture.Store(cfg)
+}
+
+// callSite returns the state of the call site with the given program counter.
+func (c *stackCaptureConfig) callSite(pc uintptr) *stackCaptureCallSite {
+	if site, ok := c.callSites.Load(pc); ok {
+		return site.(*stackCaptureCallSite) //nolint:revive
+	}
+	site := &stackCaptureCallSite{policy: c.policy}
+	if len(c.packages) > 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		for _, pkg := range c.packages {
+			if strings.HasPrefix(frame.Function, pkg.prefix) {
+				site.policy = pkg.policy
+				break
+			}
+		}
+	}
+	actual, _ := c.callSites.LoadOrStore(pc, site)
+	return actual.(*stackCaptureCallSite) //nolint:revive
+}
+
+// captureStack captures the stack according to the stack capture policy and
+// stores the program counters in pcs. It returns the number of captured
+// program counters and whether the captured stack is complete, i.e. it was
+// neither reduced nor truncated. The given number of callers of the caller of
+// captureStack are skipped.
+func captureStack(skip int, pcs *[maxStackCaptureDepth]uintptr) (int, bool) {
+	// skips this function, runtime.Callers, the caller of this function and
+	// user defined number of other callers
+	skip = 3 + max(skip, 0)
+	cfg := loadStackCaptureConfig()
+	policy := cfg.policy
+	if cfg.perCallSite {
+		if callers(policy.Backend, skip, pcs[:1]) == 0 {
+			return 0, true
+		}
+		site := cfg.callSite(pcs[0])
+		policy = site.policy
+		if policy.Mode == StackCaptureSampled {
+			if (site.count.Add(1)-1)%uint64(policy.SampleRate) != 0 {
+				return 1, false
+			}
+			policy.Mode = StackCaptureFull
+		}
+	}
+	switch policy.Mode {
+	case StackCaptureOff:
+		return 0, false
+	case StackCaptureCaller:
+		return callers(policy.Backend, skip, pcs[:1]), false
+	default:
+		n := callers(policy.Backend, skip, pcs[:policy.Depth])
+		return n, n < policy.Depth
+	}
+}
+
+// callers behaves like a call of [runtime.Callers] in the caller of callers,
+// but uses the given backend to capture the stack.
+func callers(backend StackCaptureBackend, skip int, pcs []uintptr) int {
+	// skips this function in addition
+	switch backend {
+	case StackCaptureFramePointers:
+		if n, ok := fpCallers(skip+1, pcs); ok {
+			return n
+		}
+	case StackCaptureCrossCheck:
+		n := runtime.Callers(skip+1, pcs)
+		var fpPCs [maxStackCaptureDepth]uintptr
+		if m, ok := fpCallers(skip+1, fpPCs[:len(pcs)]); ok {
+			crossCheckStacks(pcs[:n], fpPCs[:m], n == len(pcs) || m == len(pcs))
+		}
+		return n
+	}
+	return runtime.Callers(skip+1, pcs)
+}
+
+// crossCheckStacks panics if the stack captured with [runtime.Callers] differs
+// from the one captured by walking the frame pointers. The program counters of
+// inlined functions and compiler generated wrappers are ignored, because only
+// one of the backends records them. If one of the stacks is truncated, only the common
+// length is compared.
+func crossCheckStacks(callersPCs, fpPCs []uintptr, truncated bool) {
+	cache := loadSymbolCache()
+	physical := func(pcs []uintptr) []uintptr {
+		result := make([]uintptr, 0, len(pcs))
+		for i, pc := range pcs {
+			if i > 0 && cache.lookup(pcs[i-1]).next == pc {
+				continue
+			}
+			if frame, _ := runtime.CallersFrames([]uintptr{pc}).Next(); isCompilerWrapper(frame.Function, frame.File) {
+				continue
+			}
+			result = append(result, pc)
+		}
+		return result
+	}
+	expected, actual := physical(callersPCs), physical(fpPCs)
+	if truncated {
+		size := min(len(expected), len(actual))
+		expected, actual = expected[:size], actual[:size]
+	}
+	if !slices.Equal(expected, actual) {
+		panic(fmt.Sprintf("bruh: stack captured by walking the frame pointers differs from runtime.Callers:\n%s\n---\n%s",
+			stackPCString(expected), stackPCString(actual)))
+	}
+}
+
+// stackPCString returns the symbolized stack of the program counters as a
+// string.
+func stackPCString(pcs []uintptr) string {
+	stack := make(Stack, len(pcs)*2)
+	return stack[:stackPC(pcs).symbolizeUncached(stack)].String()
+}
+
+// stackCaptureDepth returns the depth of the process-wide stack capture
+// policy. It is used for stacks that are captured regardless of the mode, like
+// the stacks of panics.
+func stackCaptureDepth() int {
+	return loadStackCaptureConfig().policy.Depth
+}
@@ -0,0 +1,77 @@
+//go:build amd64 || arm64
+
+package bruh
+
+import "unsafe"
+
+// fpMaxSkip is the maximum number of frames that [fpCallers] can skip.
+const fpMaxSkip = 16
+
+// getfp returns the frame pointer of its caller.
+func getfp() unsafe.Pointer
+
+// fpCallers behaves like [runtime.Callers], but walks the frame pointers of
+// the stack instead of using the unwinder of the runtime, which is
+// considerably faster. Calls of inlined functions have no frame of their own,
+// therefore the program counters of the inlined calls are omitted, except for
+// the first one. They are restored when the stack is symbolized, as
+// [runtime.CallersFrames] does for stacks that were not returned by
+// [runtime.Callers]. It returns false if the stack cannot be captured this way,
+// e.g. because the symbol cache, which is needed to skip the frames of inlined
+// calls, is disabled.
+//
+//go:noinline
+func fpCallers(skip int, pcs []uintptr) (int, bool) {
+	cache := loadSymbolCache()
+	if cache == nil || skip < 1 || skip > fpMaxSkip {
+		return 0, false
+	}
+	// The frame pointer of this function is the first one. It leads to the
+	// return address into the caller, which is the frame with index 1, so one
+	// frame less needs to be skipped.
+	var buf [fpMaxSkip + maxStackCaptureDepth]uintptr
+	n := fpWalk(getfp(), buf[:skip-1+len(pcs)])
+
+	// skip the frames, including the frames of inlined calls, which only the
+	// symbolic information reveals
+	var i int
+	var first uintptr
+	for remaining := skip - 1; remaining > 0; {
+		if i == n {
+			return 0, false
+		}
+		pc := buf[i]
+		i++
+		for ; remaining > 0 && pc != 0; remaining-- {
+			pc = cache.lookup(pc).next
+		}
+		// skipping ended within a chain of inlined calls
+		first = pc
+	}
+
+	var m int
+	if first != 0 && len(pcs) > 0 {
+		pcs[0] = first
+		m = 1
+	}
+	m += copy(pcs[m:], buf[i:n])
+	return m, true
+}
+
+// fpWalk follows the frame pointers, starting with the given one, and stores
+// the return addresses in pcs. It returns the number of stored return
+// addresses. It must not grow the stack, because the frame pointers would be
+// invalidated.
+//
+//go:nosplit
+//go:nocheckptr
+func fpWalk(fp unsafe.Pointer, pcs []uintptr) int {
+	var i int
+	for ; i < len(pcs) && fp != nil; i++ {
+		// the return address sits one word above the frame pointer
+		pcs[i] = *(*uintptr)(unsafe.Add(fp, unsafe.Sizeof(uintptr(0))))
+		// the frame pointer points to the frame pointer of the caller
+		fp = *(*unsafe.Pointer)(fp)
+	}
+	return i
+}
@@ -0,0 +1,9 @@
+//go:build !amd64 && !arm64
+
+package bruh
+
+// fpCallers is not supported on this architecture, therefore it always returns
+// false, so that the stack is captured with [runtime.Callers] instead.
+func fpCallers(_ int, _ []uintptr) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,252 @@
+package bruh_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+// The stack capture policy is process-wide, therefore the tests in this file
+// must not run in parallel.
+
+//go:noinline
+func newStackCaptureError(msg string) *bruh.Err {
+	return bruh.New(msg).(*bruh.Err)
+}
+
+//go:noinline
+func newStackCaptureOffError(msg string) *bruh.Err {
+	return bruh.New(msg).(*bruh.Err)
+}
+
+//go:noinline
+func newStackCaptureOffCallerError(msg string) *bruh.Err {
+	return bruh.New(msg).(*bruh.Err)
+}
+
+func TestSetStackCapturePolicy(t *testing.T) { //nolint:paralleltest
+	defer bruh.ResetStackCapturePolicy()
+	assert := testutils.NewAssert(t)
+
+	assert.True(len(newStackCaptureError("full").Callers()) > 3)
+
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Depth: 3})
+	assert.Len(newStackCaptureError("depth").Callers(), 3)
+
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Mode: bruh.StackCaptureCaller})
+	err := newStackCaptureError("caller")
+	assert.Len(err.Callers(), 1)
+	assert.Equal("github.com/aisbergg/go-bruh/pkg/bruh_test.newStackCaptureError", err.Stack()[0].Name)
+
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Mode: bruh.StackCaptureOff})
+	assert.Len(newStackCaptureError("off").Callers(), 0)
+	assert.Len(bruh.Join(bruh.New("a"), bruh.New("b")).(interface{ Callers() []uintptr }).Callers(), 0)
+
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Mode: bruh.StackCaptureSampled, SampleRate: 3})
+	sizes := make([]int, 0, 7)
+	for range 7 {
+		sizes = append(sizes, len(newStackCaptureError("sampled").Callers()))
+	}
+	for i, size := range sizes {
+		if i%3 == 0 {
+			assert.True(size > 1, sizes)
+		} else {
+			assert.Equal(1, size, sizes)
+		}
+	}
+
+	bruh.ResetStackCapturePolicy()
+	assert.True(len(newStackCaptureError("reset").Callers()) > 3)
+}
+
+func TestSetPackageStackCapturePolicy(t *testing.T) { //nolint:paralleltest
+	defer bruh.ResetStackCapturePolicy()
+	assert := testutils.NewAssert(t)
+
+	bruh.SetPackageStackCapturePolicy(
+		"github.com/aisbergg/go-bruh/pkg/bruh_test.newStackCaptureOff",
+		bruh.StackCapturePolicy{Mode: bruh.StackCaptureOff},
+	)
+	// the longest prefix wins
+	bruh.SetPackageStackCapturePolicy(
+		"github.com/aisbergg/go-bruh/pkg/bruh_test.newStackCaptureOffCaller",
+		bruh.StackCapturePolicy{Mode: bruh.StackCaptureCaller},
+	)
+	assert.Len(newStackCaptureOffError("off").Callers(), 0)
+	assert.Len(newStackCaptureOffCallerError("caller").Callers(), 1)
+	assert.True(len(newStackCaptureError("full").Callers()) > 3)
+
+	// replace the policy of a prefix
+	bruh.SetPackageStackCapturePolicy(
+		"github.com/aisbergg/go-bruh/pkg/bruh_test.newStackCaptureOff",
+		bruh.StackCapturePolicy{Depth: 2},
+	)
+	assert.Len(newStackCaptureOffError("depth").Callers(), 2)
+}
+
+//go:noinline
+func newStackCaptureChain() error {
+	return bruh.Wrap(newStackCaptureError("failed"), "wrapped")
+}
+
+func TestFingerprintSampledStack(t *testing.T) { //nolint:paralleltest
+	defer bruh.ResetStackCapturePolicy()
+	assert := testutils.NewAssert(t)
+
+	// only the locations are used, because the stacks are sampled
+	opts := bruh.FingerprintOptions{LocationOnly: true}
+	full := bruh.Fingerprint(newStackCaptureChain(), opts)
+	assert.True(len(newStackCaptureChain().(*bruh.Err).Callers()) > 1)
+
+	// the first error of a call site is sampled, the others are reduced
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Mode: bruh.StackCaptureSampled, SampleRate: 100})
+	sampled := newStackCaptureChain()
+	reduced := newStackCaptureChain()
+	assert.True(len(sampled.(*bruh.Err).Callers()) > 1)
+	assert.Len(reduced.(*bruh.Err).Callers(), 1)
+	assert.Equal(full, bruh.Fingerprint(sampled, opts))
+	assert.Equal(full, bruh.Fingerprint(reduced, opts))
+
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Mode: bruh.StackCaptureCaller})
+	assert.Equal(full, bruh.Fingerprint(newStackCaptureChain(), opts))
+}
+
+func TestFormatReducedStack(t *testing.T) { //nolint:paralleltest
+	defer bruh.ResetStackCapturePolicy()
+
+	formatters := []struct {
+		name      string
+		formatter bruh.Formatter
+	}{
+		{"Bruh", bruh.BruhFormatter},
+		{"BruhFancy", bruh.BruhFancyFormatter(true, true)},
+		{"BruhStacked", bruh.BruhStackedFormatter},
+		{"BruhStackedFancy", bruh.BruhStackedFancyFormatter(true, true, true)},
+		{"GoPanic", bruh.GoPanicFormatter},
+		{"GoRuntimePanic", bruh.GoRuntimePanicFormatter},
+		{"JavaStackTrace", bruh.JavaStackTraceFormatter},
+		{"PythonTraceback", bruh.PythonTracebackFormatter},
+	}
+	policies := []struct {
+		name   string
+		policy bruh.StackCapturePolicy
+		// all applies the policy to the whole chain instead of the innermost
+		// error only
+		all bool
+	}{
+		{"Off", bruh.StackCapturePolicy{Mode: bruh.StackCaptureOff}, false},
+		{"AllOff", bruh.StackCapturePolicy{Mode: bruh.StackCaptureOff}, true},
+		{"Caller", bruh.StackCapturePolicy{Mode: bruh.StackCaptureCaller}, false},
+		{"AllCaller", bruh.StackCapturePolicy{Mode: bruh.StackCaptureCaller}, true},
+	}
+
+	for _, p := range policies {
+		bruh.SetStackCapturePolicy(p.policy)
+		inner := newStackCaptureError("inner")
+		if !p.all {
+			// mix errors with full and reduced stacks
+			bruh.ResetStackCapturePolicy()
+		}
+		err := bruh.Wrap(bruh.Wrap(inner, "middle"), "outer")
+		bruh.ResetStackCapturePolicy()
+
+		for _, f := range formatters {
+			t.Run(p.name+"/"+f.name, func(t *testing.T) {
+				result := bruh.StringFormat(err, f.formatter)
+				for _, msg := range []string{"outer", "middle", "inner"} {
+					if !strings.Contains(result, msg) {
+						t.Errorf("expected output to contain %q, got:\n%s", msg, result)
+					}
+				}
+			})
+		}
+
+		t.Run(p.name+"/Unpack", func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			bruh.StringFormat(err, func(b []byte, unpacker *bruh.Unpacker) []byte {
+				upkErr := unpacker.Unpack()
+				assert.Len(upkErr, 3)
+				assert.Equal("inner", upkErr[2].Msg)
+				assert.True(len(upkErr[2].Stack) <= 1)
+				return b
+			})
+			assert.Equal(bruh.String(err), bruh.String(bruh.NewReport(err).Err()))
+		})
+	}
+}
+
+// inlinedStackCaptureError is inlined into its callers, so that its frame is
+// only recorded by runtime.Callers.
+func inlinedStackCaptureError(msg string) error {
+	return bruh.Wrap(newStackCaptureError(msg), "inlined")
+}
+
+type stackCaptureErrorer struct{}
+
+func (stackCaptureErrorer) Error(msg string) error {
+	return bruh.Errorf("method: %s", msg)
+}
+
+func TestStackCaptureBackend(t *testing.T) { //nolint:paralleltest
+	defer bruh.ResetStackCapturePolicy()
+	assert := testutils.NewAssert(t)
+
+	newErrors := func() []error {
+		var wg sync.WaitGroup
+		var goErr error
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			goErr = inlinedStackCaptureError("goroutine")
+		}()
+		wg.Wait()
+		method := stackCaptureErrorer{}.Error
+		var panicErr error
+		func() {
+			defer func() {
+				panicErr = bruh.Wrap(bruh.NewFromPanic(recover()), "recovered")
+			}()
+			panic("panic")
+		}()
+		return []error{
+			newStackCaptureError("new"),
+			bruh.NewSkip(1, "skip"),
+			inlinedStackCaptureError("inlined"),
+			bruh.Wrap(inlinedStackCaptureError("inner"), "outer"),
+			method("value"),
+			goErr,
+			panicErr,
+		}
+	}
+
+	// the stacks of both backends are equal
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Backend: bruh.StackCaptureCrossCheck})
+	newErrors()
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Backend: bruh.StackCaptureCrossCheck, Depth: 3})
+	newErrors()
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{
+		Backend: bruh.StackCaptureCrossCheck,
+		Mode:    bruh.StackCaptureSampled, SampleRate: 2,
+	})
+	newErrors()
+
+	// the errors are formatted the same
+	formatted := make([][]string, 0, 2)
+	for i, backend := range []bruh.StackCaptureBackend{bruh.StackCaptureCallers, bruh.StackCaptureFramePointers} {
+		bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Backend: backend})
+		formatted = append(formatted, nil)
+		for _, err := range newErrors() {
+			formatted[i] = append(formatted[i], bruh.StringFormat(err, bruh.BruhStackedFormatter))
+		}
+	}
+	assert.Equal(formatted[0], formatted[1])
+
+	// falls back to runtime.Callers if the symbol cache is disabled
+	defer bruh.SetSymbolCacheSize(bruh.DefaultSymbolCacheSize)
+	bruh.SetSymbolCacheSize(0)
+	bruh.SetStackCapturePolicy(bruh.StackCapturePolicy{Backend: bruh.StackCaptureFramePointers})
+	assert.True(len(newStackCaptureError("fallback").Callers()) > 3)
+}
@@ -0,0 +1,159 @@
+package bruh
+
+import (
+	"container/list"
+	"hash/maphash"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// StackInterningOptions configures the stack interning enabled by
+// [EnableStackInterning].
+type StackInterningOptions struct {
+	// MaxStacks is the maximum number of distinct stacks that are kept. If it
+	// is exceeded, the least recently used stack is dropped. Defaults to 4096.
+	MaxStacks int
+}
+
+// stackInterning is the active stack interner. It is nil if stack interning is
+// disabled.
+var stackInterning atomic.Pointer[stackInterner]
+
+// EnableStackInterning enables the interning of stacks. Errors with identical
+// stacks, e.g. because they were created at the same call site, share a single
+// immutable copy of the program counters instead of storing their own. The
+// stacks symbolized by the formatters are cached as well, so that identical
+// stacks are symbolized only once. This is useful for services that produce
+// the same errors at a high rate. The memory used by the interned stacks is
+// bounded by [StackInterningOptions.MaxStacks].
+//
+// Enabling the interning again replaces the previous interner and its cache.
+func EnableStackInterning(opts ...StackInterningOptions) {
+	var o StackInterningOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.MaxStacks <= 0 {
+		o.MaxStacks = 4096
+	}
+	stackInterning.Store(&stackInterner{
+		seed:    maphash.MakeSeed(),
+		max:     o.MaxStacks,
+		entries: make(map[uint64]*list.Element, o.MaxStacks),
+		lru:     list.New(),
+	})
+}
+
+// DisableStackInterning disables the interning of stacks and drops the cached
+// stacks. Errors that were created before keep sharing their stacks.
+func DisableStackInterning() {
+	stackInterning.Store(nil)
+}
+
+// internedStack is an interned stack. The program counters and the symbolized
+// stack are immutable once set.
+type internedStack struct {
+	hash uint64
+	pcs  []uintptr
+	// stack is the symbolized stack. It is nil until the stack is symbolized.
+	stack Stack
+}
+
+// stackInterner is a cache of stacks, bounded by an LRU.
+type stackInterner struct {
+	seed    maphash.Seed
+	max     int
+	mu      sync.Mutex
+	entries map[uint64]*list.Element // of *internedStack
+	lru     *list.List
+}
+
+// hash returns the hash of the given program counters.
+func (in *stackInterner) hash(pcs []uintptr) uint64 {
+	return maphash.Bytes(in.seed, unsafe.Slice(
+		(*byte)(unsafe.Pointer(unsafe.SliceData(pcs))),
+		len(pcs)*int(unsafe.Sizeof(uintptr(0))),
+	))
+}
+
+// get returns the interned stack of the given program counters and marks it
+// as recently used. If it isn't interned, nil is returned. in.mu must be held.
+func (in *stackInterner) get(hash uint64, pcs []uintptr) *internedStack {
+	elem, ok := in.entries[hash]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*internedStack) //nolint:revive
+	if !slices.Equal(entry.pcs, pcs) {
+		return nil
+	}
+	in.lru.MoveToFront(elem)
+	return entry
+}
+
+// lookup returns the interned stack of the given program counters and marks it
+// as recently used. If it isn't interned yet, it is added and the least
+// recently used stack is dropped if necessary. in.mu must be held.
+func (in *stackInterner) lookup(hash uint64, pcs []uintptr) *internedStack {
+	if entry := in.get(hash, pcs); entry != nil {
+		return entry
+	}
+	if elem, ok := in.entries[hash]; ok {
+		// hash collision: the more recent stack replaces the older one
+		in.lru.Remove(elem)
+		delete(in.entries, hash)
+	}
+	if in.lru.Len() >= in.max {
+		oldest := in.lru.Back()
+		in.lru.Remove(oldest)
+		delete(in.entries, oldest.Value.(*internedStack).hash) //nolint:revive
+	}
+	entry := &internedStack{hash: hash, pcs: slices.Clip(slices.Clone(pcs))}
+	in.entries[hash] = in.lru.PushFront(entry)
+	return entry
+}
+
+// intern returns the shared copy of the given program counters.
+func (in *stackInterner) intern(pcs []uintptr) []uintptr {
+	hash := in.hash(pcs)
+	in.mu.Lock()
+	entry := in.lookup(hash, pcs)
+	in.mu.Unlock()
+	return entry.pcs
+}
+
+// toStack behaves like [stackPC.toStack], but symbolizes identical stacks only
+// once. Only the stacks that were interned by [stackInterner.intern] are
+// cached. Other stacks, like the reconstructed stacks of wrapping errors, are
+// symbolized without caching, so that they don't evict the interned ones.
+func (in *stackInterner) toStack(pcs stackPC, stack Stack) int {
+	hash := in.hash(pcs)
+	in.mu.Lock()
+	entry := in.get(hash, pcs)
+	var cached Stack
+	if entry != nil {
+		cached = entry.stack
+	}
+	in.mu.Unlock()
+	if cached != nil {
+		return copy(stack, cached)
+	}
+
+	n := pcs.symbolize(stack)
+	// stacks truncated by the size of the given slice are not cached
+	if entry != nil && n < len(stack) {
+		symbolized := slices.Clone(stack[:n])
+		if symbolized == nil {
+			symbolized = Stack{}
+		}
+		in.mu.Lock()
+		// the entry may have been dropped while the lock was released
+		if elem, ok := in.entries[hash]; ok && elem.Value.(*internedStack) == entry { //nolint:revive
+			entry.stack = symbolized
+		}
+		in.mu.Unlock()
+	}
+	return n
+}
@@ -0,0 +1,84 @@
+package bruh_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+// Stack interning is process-wide, therefore the tests in this file must not
+// run in parallel.
+
+//go:noinline
+func newInternedError(msg string) *bruh.Err {
+	return bruh.New(msg).(*bruh.Err)
+}
+
+func TestStackInterning(t *testing.T) { //nolint:paralleltest
+	defer bruh.DisableStackInterning()
+	assert := testutils.NewAssert(t)
+
+	errs := make([]*bruh.Err, 0, 3)
+	for range 2 {
+		errs = append(errs, newInternedError("not interned"))
+	}
+	assert.False(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(errs[1].Callers()))
+
+	bruh.EnableStackInterning()
+	errs = errs[:0]
+	for range 3 {
+		errs = append(errs, newInternedError("interned"))
+	}
+	assert.Equal(errs[0].Callers(), errs[2].Callers())
+	assert.True(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(errs[1].Callers()))
+	assert.True(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(errs[2].Callers()))
+	// the cached symbolized stacks are formatted the same
+	formatted := bruh.String(errs[0])
+	assert.Equal(formatted, bruh.String(errs[0]))
+	assert.Equal(formatted, bruh.String(errs[1]))
+
+	// errors created before keep their stacks
+	bruh.DisableStackInterning()
+	assert.Equal(formatted, bruh.String(errs[0]))
+	assert.True(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(errs[1].Callers()))
+	assert.False(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(newInternedError("disabled").Callers()))
+}
+
+func TestStackInterningMaxStacks(t *testing.T) { //nolint:paralleltest
+	defer bruh.DisableStackInterning()
+	assert := testutils.NewAssert(t)
+
+	bruh.EnableStackInterning(bruh.StackInterningOptions{MaxStacks: 1})
+	errs := make([]*bruh.Err, 0, 3)
+	for i := range 3 {
+		if i == 2 {
+			// a different stack evicts the least recently used one
+			_ = bruh.New("other")
+		}
+		errs = append(errs, newInternedError("interned"))
+	}
+	assert.True(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(errs[1].Callers()))
+	assert.False(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(errs[2].Callers()))
+	assert.Equal(errs[0].Callers(), errs[2].Callers())
+}
+
+func TestStackInterningFormatDoesNotEvict(t *testing.T) { //nolint:paralleltest
+	defer bruh.DisableStackInterning()
+	assert := testutils.NewAssert(t)
+
+	notInterned := bruh.Wrap(bruh.New("not interned"), "wrapped")
+	bruh.EnableStackInterning(bruh.StackInterningOptions{MaxStacks: 1})
+	errs := make([]*bruh.Err, 0, 2)
+	for i := range 2 {
+		if i == 1 {
+			// formatting stacks that weren't interned must not evict the
+			// interned one
+			_ = bruh.String(notInterned)
+		}
+		errs = append(errs, newInternedError("interned"))
+	}
+	assert.True(unsafe.SliceData(errs[0].Callers()) == unsafe.SliceData(errs[1].Callers()))
+	assert.Equal(bruh.String(errs[0]), bruh.String(errs[1]))
+}
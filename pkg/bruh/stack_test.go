@@ -713,3 +713,119 @@ func errorFn49() error {
 func errorFn50() error {
 	return New("root cause")
 }
+
+// flattenStacks returns a copy of the error chain, whose stacks are not
+// delta-encoded.
+func flattenStacks(err error) error {
+	e, ok := err.(*Err)
+	if !ok {
+		return err
+	}
+	return &Err{msg: e.Message(), err: flattenStacks(e.err), stack: e.Callers()}
+}
+
+func TestDeltaStack(t *testing.T) {
+	assert := testutils.NewAssert(t)
+
+	err := ProcessFile("example.json", false, false).(*Err)
+	readErr := err.err.(*Err)
+	rootErr := readErr.err.(*Err)
+	// the wrapping errors store only the frames that are not shared with the
+	// wrapped errors; the shared frames of err are all stored by rootErr
+	assert.True(err.suffix == rootErr)
+	assert.Len(err.stack, 1)
+	assert.True(readErr.suffix == rootErr)
+	assert.Len(readErr.stack, 1)
+	assert.True(rootErr.suffix == nil)
+
+	// the full stack is reconstructed
+	callers := err.Callers()
+	assert.Len(callers, err.callersLen())
+	stack := make(Stack, len(callers))
+	stack = stack[:stackPC(callers).toStack(stack)]
+	assert.Len(stack, 3)
+	assert.Equal(processFunc, filepath.Base(stack[0].Name))
+	assert.Equal(prefix+t.Name(), filepath.Base(stack[1].Name))
+	assert.Equal(readErr.Callers()[readErr.callersLen()-2:], callers[len(callers)-2:])
+	assert.Equal(String(flattenStacks(err)), String(err))
+	assert.Equal(StringFormat(flattenStacks(err), BruhStackedFormatter), StringFormat(err, BruhStackedFormatter))
+
+	// truncated stacks are stored in full
+	deep := errorFn1().(*Err)
+	delta := 0
+	for e := deep; e != nil; e, _ = e.err.(*Err) {
+		if e.suffix != nil {
+			assert.Len(e.stack, 1)
+			delta++
+		} else {
+			assert.Len(e.stack, e.callersLen())
+		}
+	}
+	assert.True(delta > 0 && delta < 50, delta)
+	assert.Equal(StringFormat(flattenStacks(deep), BruhStackedFormatter), StringFormat(deep, BruhStackedFormatter))
+}
+
+func TestSymbolCache(t *testing.T) { //nolint:paralleltest
+	assert := testutils.NewAssert(t)
+
+	symbolize := func(pcs []uintptr) (Stack, Stack) {
+		uncached := make(Stack, MaxChainStackDepth)
+		uncached = uncached[:stackPC(pcs).symbolizeUncached(uncached)]
+		cached := make(Stack, MaxChainStackDepth)
+		cached = cached[:newSymbolCache(DefaultSymbolCacheSize).toStack(pcs, cached)]
+		return uncached, cached
+	}
+
+	// the frames of inlined functions have program counters of their own
+	var rootErr *Err
+	for err := ProcessFile("example.json", false, false); err != nil; err = errors.Unwrap(err) {
+		rootErr, _ = err.(*Err)
+	}
+	pcs := rootErr.Callers()
+	uncached, cached := symbolize(pcs)
+	assert.True(len(cached) > 2)
+	assert.Equal(uncached, cached)
+
+	// the frames of inlined functions are inserted, if their program counters
+	// are missing, e.g. because they were parsed from a panic output
+	var inlined int
+	withoutInlined := make([]uintptr, 0, len(pcs))
+	for i := 0; i < len(pcs); i++ {
+		withoutInlined = append(withoutInlined, pcs[i])
+		if next := resolvePC(pcs[i]).next; next != 0 && i+1 < len(pcs) && pcs[i+1] == next {
+			inlined++
+			i++
+		}
+	}
+	assert.True(inlined > 0)
+	full := cached
+	uncached, cached = symbolize(withoutInlined)
+	assert.Equal(full, cached)
+	// unlike the cache, the uncached symbolization assigns the program counters
+	// by the index of the frames, which is off for the inserted frames
+	for i := range uncached {
+		uncached[i].ProgramCounter = cached[i].ProgramCounter
+	}
+	assert.Equal(uncached, cached)
+
+	// globally defined errors have no stack
+	uncached, cached = symbolize(errEOF.(*Err).Callers())
+	assert.Len(uncached, 0)
+	assert.Len(cached, 0)
+
+	// the formatting is the same with and without cache
+	defer SetSymbolCacheSize(DefaultSymbolCacheSize)
+	err := ProcessFile("example.json", false, false)
+	expected := StringFormat(err, BruhStackedFormatter)
+	SetSymbolCacheSize(0)
+	assert.True(loadSymbolCache() == nil)
+	assert.Equal(expected, StringFormat(err, BruhStackedFormatter))
+
+	// the size of the cache is bounded
+	SetSymbolCacheSize(symbolCacheShards)
+	cache := loadSymbolCache()
+	assert.Equal(expected, StringFormat(err, BruhStackedFormatter))
+	for i := range cache.shards {
+		assert.True(len(cache.shards[i].entries) <= 1)
+	}
+}
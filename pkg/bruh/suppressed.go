@@ -0,0 +1,136 @@
+package bruh
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// AddSuppressed attaches the given errors as suppressed errors to the error
+// stored in the variable pointed to by errp. This is useful when a cleanup, e.g.
+// a deferred Close, Rollback or Unlock, fails while another error is already
+// being returned: the primary error is kept, and the cleanup error isn't lost.
+// Suppressed errors are not part of the error chain, so [errors.Is] and
+// [errors.As] only consider the primary error. They are exposed by the
+// [Unpacker] and rendered by the formatters. Use [Suppressed] to retrieve them.
+//
+// If errp holds a [*Err] or an error that embeds it, e.g. a [*PanicErr], the
+// suppressed errors are attached to the error itself, so that its dynamic type
+// and identity are kept. Since the error is modified, do not use AddSuppressed
+// on errors that are shared, e.g. sentinel errors. Other errors are wrapped by
+// an error that holds the suppressed errors. In that case, comparisons like
+// `err == io.EOF` and type assertions like `err.(*MyErr)` no longer match the
+// primary error, use [errors.Is] and [errors.As] instead.
+//
+// If errp holds no error, the first of the given errors is stored instead and
+// the others are suppressed by it. Nil errors are left out.
+//
+// Example usage:
+//
+//	func transfer(tx *sql.Tx) (err error) {
+//	    defer func() {
+//	        if err != nil {
+//	            bruh.AddSuppressed(&err, tx.Rollback())
+//	        }
+//	    }()
+//	    ...
+//	}
+func AddSuppressed(errp *error, suppressed ...error) {
+	for _, serr := range suppressed {
+		if serr == nil {
+			continue
+		}
+		switch err := (*errp).(type) {
+		case nil:
+			*errp = serr
+		case *suppressedErr:
+			// copy the errors, so that errors returned earlier stay unchanged
+			*errp = &suppressedErr{
+				err:        err.err,
+				suppressed: append(slices.Clip(err.suppressed), serr),
+			}
+		case suppressor:
+			err.addSuppressed(serr)
+		default:
+			*errp = &suppressedErr{err: err, suppressed: []error{serr}}
+		}
+	}
+}
+
+// CloseAndCapture closes c and attaches the error of the close, if any, to the
+// error stored in the variable pointed to by errp, see [AddSuppressed]. The
+// error of the close is wrapped with a stack trace of the caller. It is meant
+// to be called by a defer statement.
+//
+// Example usage:
+//
+//	func readConfig(path string) (err error) {
+//	    f, err := os.Open(path)
+//	    if err != nil {
+//	        return bruh.Wrap(err, "opening config")
+//	    }
+//	    defer bruh.CloseAndCapture(&err, f)
+//	    ...
+//	}
+func CloseAndCapture(errp *error, c io.Closer) {
+	if cerr := c.Close(); cerr != nil {
+		AddSuppressed(errp, WrapfSkip(cerr, 1, "closing %T", c))
+	}
+}
+
+// Suppressed returns the suppressed errors that are attached to err or any of
+// the errors wrapped by it, see [AddSuppressed]. If there are none, nil is
+// returned.
+func Suppressed(err error) []error {
+	var suppressed []error
+	for ; err != nil; err = unwrapFirst(err) {
+		if serr, ok := err.(*suppressedErr); ok {
+			suppressed = append(suppressed, serr.suppressed...)
+		} else if serr, ok := err.(suppressor); ok {
+			suppressed = append(suppressed, serr.suppressedErrors()...)
+		}
+	}
+	return suppressed
+}
+
+// -----------------------------------------------------------------------------
+
+// suppressor is implemented by errors that hold their suppressed errors
+// themselves, see [AddSuppressed].
+type suppressor interface {
+	suppressedErrors() []error
+	addSuppressed(err error)
+}
+
+// suppressedErrors returns the errors that were suppressed by this error.
+func (e *Err) suppressedErrors() []error {
+	return e.suppressed
+}
+
+// addSuppressed attaches a suppressed error to this error.
+func (e *Err) addSuppressed(err error) {
+	e.suppressed = append(e.suppressed, err)
+}
+
+// suppressedErr attaches suppressed errors to an error. It neither has a
+// message nor a stack of its own and is merged into the wrapped error when
+// unpacked.
+type suppressedErr struct { //nolint: errname
+	err        error
+	suppressed []error
+}
+
+// Error returns the message of the wrapped error.
+func (e *suppressedErr) Error() string {
+	return e.err.Error()
+}
+
+// Format implements the fmt.Formatter interface. See [Err.Format] for details.
+func (e *suppressedErr) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+// Unwrap returns the wrapped error.
+func (e *suppressedErr) Unwrap() error {
+	return e.err
+}
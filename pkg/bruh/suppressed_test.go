@@ -0,0 +1,149 @@
+package bruh_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+type failingCloser struct {
+	err error
+}
+
+func (c failingCloser) Close() error { return c.err }
+
+func closeWithPrimary(primary error, c failingCloser) (err error) {
+	defer bruh.CloseAndCapture(&err, c)
+	return primary
+}
+
+func TestAddSuppressed(t *testing.T) {
+	t.Parallel()
+	first := errors.New("first")
+	second := bruh.New("second")
+
+	t.Run("NilPrimary", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		var err error
+		bruh.AddSuppressed(&err, nil, first, second)
+		assert.Equal("first", err.Error())
+		assert.Equal([]error{second}, bruh.Suppressed(err))
+	})
+
+	t.Run("NilSuppressed", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		primary := bruh.New("primary")
+		err := primary
+		bruh.AddSuppressed(&err, nil)
+		assert.Equal(primary, err)
+	})
+
+	t.Run("KeepsPrimary", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		primary := bruh.New("primary")
+		err := primary
+		bruh.AddSuppressed(&err, first)
+		bruh.AddSuppressed(&err, second)
+		// the suppressed errors are attached to the primary error itself
+		assert.True(err == primary, "expected the primary error to be kept")
+		assert.False(errors.Is(err, first), "suppressed errors must not be part of the chain")
+		assert.Equal([]error{first, second}, bruh.Suppressed(bruh.Wrap(err, "context")))
+		assert.Equal([]error{first, second}, bruh.Suppressed(primary))
+	})
+
+	t.Run("KeepsType", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := bruh.NewFromPanic("panicked")
+		bruh.AddSuppressed(&err, first)
+		_, ok := err.(*bruh.PanicErr)
+		assert.True(ok, "expected the type of the primary error to be kept")
+		assert.Equal([]error{first}, bruh.Suppressed(err))
+	})
+
+	t.Run("ForeignPrimary", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := io.EOF
+		bruh.AddSuppressed(&err, first)
+		assert.Equal("EOF", err.Error())
+		assert.True(errors.Is(err, io.EOF))
+		assert.Equal([]error{first}, bruh.Suppressed(err))
+	})
+
+	t.Run("ReturnedErrorsStayUnchanged", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		err := io.ErrUnexpectedEOF
+		bruh.AddSuppressed(&err, first)
+		returned := err
+		bruh.AddSuppressed(&err, second)
+		assert.Equal([]error{first}, bruh.Suppressed(returned))
+		assert.Equal([]error{first, second}, bruh.Suppressed(err))
+	})
+}
+
+func TestCloseAndCapture(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+	closeErr := errors.New("already closed")
+
+	err := closeWithPrimary(nil, failingCloser{})
+	assert.Nil(err)
+
+	err = closeWithPrimary(nil, failingCloser{err: closeErr})
+	assert.True(errors.Is(err, closeErr))
+	assert.Equal("closing bruh_test.failingCloser: already closed", err.Error())
+
+	primary := bruh.New("primary")
+	err = closeWithPrimary(primary, failingCloser{err: closeErr})
+	assert.True(err == primary, "expected the primary error to be kept")
+	suppressed := bruh.Suppressed(err)
+	assert.Len(suppressed, 1)
+	assert.True(errors.Is(suppressed[0], closeErr))
+	stack := suppressed[0].(interface{ Stack() bruh.Stack }).Stack()
+	assert.True(len(stack) > 0 && strings.HasSuffix(stack[0].Name, "closeWithPrimary"),
+		"expected the stack of the close error to start at the deferred call")
+}
+
+func TestFormatSuppressed(t *testing.T) {
+	t.Parallel()
+	err := bruh.New("primary")
+	bruh.AddSuppressed(&err, bruh.New("cleanup"))
+	err = bruh.Wrap(err, "context")
+
+	t.Run("Unpacker", func(t *testing.T) {
+		assert := testutils.NewAssert(t)
+		bruh.StringFormat(err, func(b []byte, unpacker *bruh.Unpacker) []byte {
+			upkErr := unpacker.Unpack()
+			assert.Len(upkErr, 2)
+			assert.Len(upkErr[0].Suppressed, 0)
+			assert.Len(upkErr[1].Suppressed, 1)
+			assert.Equal("cleanup", upkErr[1].Suppressed[0].Error())
+			return b
+		})
+	})
+
+	assertFormat := func(name string, f bruh.Formatter, exp ...string) {
+		t.Helper()
+		t.Run(name, func(t *testing.T) {
+			out := bruh.StringFormat(err, f)
+			for _, e := range exp {
+				if !strings.Contains(out, e) {
+					t.Errorf("expected output to contain %q, got:\n%s", e, out)
+				}
+			}
+		})
+	}
+
+	assertFormat("Bruh", bruh.BruhFormatter, "context: primary\n", "\nsuppressed: cleanup\n    at ")
+	assertFormat("BruhStacked", bruh.BruhStackedFormatter, "\nprimary\n", "\n    suppressed: cleanup\n        at ")
+	assertFormat("Java", bruh.JavaStackTraceFormatter, "\nCaused by: *bruh.Err: primary\n", "\n    Suppressed: *bruh.Err: cleanup\n        at ")
+	assertFormat(
+		"Python",
+		bruh.PythonTracebackFormatter,
+		"*bruh.Err: primary\n\nDuring handling of the above exception, another exception occurred:\n\nTraceback (most recent call last):\n",
+		"*bruh.Err: cleanup\n\nThe above exception was the direct cause of the following exception:\n",
+	)
+}
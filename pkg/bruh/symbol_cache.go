@@ -0,0 +1,211 @@
+package bruh
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSymbolCacheSize is the default maximum number of program counters
+// whose symbolic information is cached.
+const DefaultSymbolCacheSize = 8192
+
+// symbolCacheShards is the number of independently locked parts of the symbol
+// cache. It must be a power of two.
+const symbolCacheShards = 16
+
+var (
+	activeSymbolCache atomic.Pointer[symbolCache]
+	// defaultSymbolCache is used until the size is changed. Unlike an init
+	// function, it is also available to errors formatted during the
+	// initialization of the package variables.
+	defaultSymbolCache = newSymbolCache(DefaultSymbolCacheSize)
+)
+
+// SetSymbolCacheSize sets the maximum number of program counters whose symbolic
+// information, i.e. function name, file and line, is cached. Resolving program
+// counters is the most expensive part of formatting an error, and the same
+// program counters are resolved over and over again when errors are created at
+// the same call sites. The cache is enabled by default with a size of
+// [DefaultSymbolCacheSize]. If the cache is full, an arbitrary entry is dropped.
+// A size of zero or less disables the cache. Changing the size drops the cached
+// entries.
+func SetSymbolCacheSize(size int) {
+	activeSymbolCache.Store(newSymbolCache(size))
+}
+
+// loadSymbolCache returns the active symbol cache. It is nil if the cache is
+// disabled.
+func loadSymbolCache() *symbolCache {
+	if cache := activeSymbolCache.Load(); cache != nil {
+		return cache.enabled()
+	}
+	return defaultSymbolCache
+}
+
+// symbolizedPC is the cached symbolic information of a program counter. It is
+// immutable.
+type symbolizedPC struct {
+	// frames are the frames of the program counter that appear in stacks, see
+	// [stackFrameName]. The ProgramCounter is set when the frames are copied
+	// into a stack.
+	frames []StackFrame
+	// global is true if the program counter belongs to the initialization of
+	// a globally defined error.
+	global bool
+	// next is the program counter of the function that a function was inlined
+	// into, as returned by [runtime.Callers]. It is zero if the function was
+	// not inlined. [runtime.CallersFrames] inserts the frame of the outer
+	// function if it doesn't follow in the list of program counters, e.g.
+	// because they were parsed from a panic output.
+	next uintptr
+}
+
+// symbolCache is a size bounded cache that maps program counters to their
+// symbolic information. It is safe for concurrent use.
+type symbolCache struct {
+	shards [symbolCacheShards]symbolCacheShard
+}
+
+// symbolCacheShard is an independently locked part of a [symbolCache].
+type symbolCacheShard struct {
+	mu      sync.RWMutex
+	max     int
+	entries map[uintptr]*symbolizedPC
+}
+
+// newSymbolCache creates a new symbol cache with the given maximum number of
+// entries. The returned cache is disabled if size is zero or less.
+func newSymbolCache(size int) *symbolCache {
+	cache := &symbolCache{}
+	if size <= 0 {
+		return cache
+	}
+	perShard := max(size/symbolCacheShards, 1)
+	for i := range cache.shards {
+		cache.shards[i].max = perShard
+		cache.shards[i].entries = make(map[uintptr]*symbolizedPC, min(perShard, 64))
+	}
+	return cache
+}
+
+// enabled returns the cache, or nil if it is disabled.
+func (c *symbolCache) enabled() *symbolCache {
+	if c.shards[0].entries == nil {
+		return nil
+	}
+	return c
+}
+
+// lookup returns the symbolic information of the program counter, resolving
+// and caching it if necessary.
+func (c *symbolCache) lookup(pc uintptr) *symbolizedPC {
+	// Fibonacci hashing spreads the program counters, which are close to each
+	// other, evenly across the shards
+	shard := &c.shards[(uint64(pc)*0x9e3779b97f4a7c15)>>60&(symbolCacheShards-1)]
+	shard.mu.RLock()
+	entry, ok := shard.entries[pc]
+	shard.mu.RUnlock()
+	if ok {
+		return entry
+	}
+
+	entry = resolvePC(pc)
+	shard.mu.Lock()
+	if len(shard.entries) >= shard.max {
+		for evicted := range shard.entries {
+			delete(shard.entries, evicted)
+			break
+		}
+	}
+	shard.entries[pc] = entry
+	shard.mu.Unlock()
+	return entry
+}
+
+// resolvePC resolves the symbolic information of a program counter.
+func resolvePC(pc uintptr) *symbolizedPC {
+	// The invalid program counter that follows makes CallersFrames return the
+	// frame of the outer function if pc belongs to an inlined function.
+	frames := runtime.CallersFrames([]uintptr{pc, 0})
+	entry := &symbolizedPC{}
+	var inlined bool
+	for n := 0; ; n++ {
+		frame, more := frames.Next()
+		if frame.Function == "" && frame.PC == 0 {
+			break
+		}
+		if n == 0 {
+			// CallersFrames provides no function for inlined calls
+			inlined = frame.Func == nil && frame.Entry != 0
+		} else if inlined {
+			// the frame of the outer function of an inlined function
+			entry.next = frame.PC + 1
+			break
+		}
+		if isGloballyDefinedError(frame.Function) {
+			entry.global = true
+		}
+		if name, ok := stackFrameName(frame.Function, frame.File); ok {
+			sf := StackFrame{
+				Name:            name,
+				File:            frame.File,
+				Line:            frame.Line,
+				ProgramCounter2: frame.PC,
+			}
+			if frame.Func != nil && frame.PC >= frame.Entry {
+				// CallersFrames reduced the return address by 1, see
+				// [stackPC.symbolizeUncached]
+				sf.Offset = frame.PC + 1 - frame.Entry
+			}
+			entry.frames = append(entry.frames, sf)
+		}
+		if !more {
+			break
+		}
+	}
+	return entry
+}
+
+// toStack behaves like [stackPC.symbolize], but looks up the symbolic
+// information of the program counters in the cache.
+func (c *symbolCache) toStack(pcs stackPC, stack Stack) int {
+	var i int
+	for j := 0; j < len(pcs) && i < len(stack); j++ {
+		pc := pcs[j]
+		for pc != 0 && i < len(stack) {
+			entry := c.lookup(pc)
+			// discard stack for globally defined errors
+			if entry.global {
+				return 0
+			}
+			for _, frame := range entry.frames {
+				if i == len(stack) {
+					break
+				}
+				frame.ProgramCounter = pc
+				stack[i] = frame
+				i++
+			}
+			// insert the frame of the outer function of an inlined function,
+			// unless it follows anyway
+			pc = 0
+			if entry.next != 0 && j+1 < len(pcs) && pcs[j+1] != entry.next {
+				pc = entry.next
+			}
+		}
+	}
+	return i
+}
+
+// isGloballyDefinedErrorPC returns true if the program counter belongs to the
+// initialization of a globally defined error.
+func isGloballyDefinedErrorPC(pc uintptr) bool {
+	if cache := loadSymbolCache(); cache != nil {
+		return cache.lookup(pc).global
+	}
+	frames := allocFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+	disposeFrames(frames)
+	return isGloballyDefinedError(frame.Function)
+}
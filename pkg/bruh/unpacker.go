@@ -16,6 +16,7 @@ type Unpacker struct {
 	cbdStk    *Stack         // A pointer to the combined stack of all errors.
 	chainLen  int            // The length of the error chain.
 	unpackAll bool           // Indicates whether errors without a trace should get a separate entry in upkErr or shall be "pooled" together.
+	pcOnly    bool           // Indicates whether the symbolization of program counters shall be skipped.
 }
 
 // unpackerPool is a sync.Pool for reusing Unpacker instances to reduce allocations.
@@ -27,14 +28,10 @@ var unpackerPool = sync.Pool{
 // and retrieves a previously allocated Unpacker from the pool if available, initializing it with
 // the provided error and unpackAll flag.
 func newUnpacker(err error, unpackAll bool) *Unpacker {
-	chainLen := 0
-	for uerr := err; uerr != nil; uerr = Unwrap(uerr) {
-		chainLen++
-	}
 	unpacker := unpackerPool.Get().(*Unpacker) //nolint:revive
 	*unpacker = Unpacker{
 		err:       err,
-		chainLen:  chainLen,
+		chainLen:  treeSize(err),
 		unpackAll: unpackAll,
 	}
 	return unpacker
@@ -62,12 +59,34 @@ func (u *Unpacker) Error() error {
 	return u.err
 }
 
-// ChainLen returns the length of the error chain (number of wrapped errors).
+// Goroutine returns the goroutine the error occurred in, if known. If the
+// chain contains errors of several goroutines, e.g. because a goroutine
+// started with [Go] returned the error of another goroutine, the goroutine of
+// the deepest error of the first branch is returned.
+func (u *Unpacker) Goroutine() *Goroutine {
+	var gr *Goroutine
+	for uerr := u.err; uerr != nil; uerr = unwrapFirst(uerr) {
+		if gerr, ok := uerr.(goroutiner); ok {
+			if g := gerr.Goroutine(); g != nil {
+				gr = g
+			}
+		}
+	}
+	return gr
+}
+
+// ChainLen returns the length of the error chain (number of wrapped errors). If
+// the chain branches into an error tree, all errors of the tree are counted.
 func (u *Unpacker) ChainLen() int {
 	return u.chainLen
 }
 
 // Unpack processes and returns the unpacked error representation.
+//
+// Errors that wrap multiple errors (`Unwrap() []error`) turn the chain into a
+// tree. The elements of the tree are returned in depth-first order, and the
+// relationship between the elements is described by [UnpackedElement.Parent]
+// and [UnpackedElement.Children].
 func (u *Unpacker) Unpack() UnpackedError {
 	if u.upkErr != nil {
 		return *u.upkErr
@@ -75,82 +94,213 @@ func (u *Unpacker) Unpack() UnpackedError {
 	if u.err == nil {
 		return UnpackedError{}
 	}
-	err := u.err
 	upkErrPtr := newUnpackedError(u.chainLen)
 	upkErr := *upkErrPtr
-	prvStack := Stack{}
+	n := u.unpackTree(upkErr, 0, -1, u.err, Stack{})
 
-	i := 0
+	// At this point len(upkErr) == cap(upkErr), thus we have to trim the slice
+	// down to its actual size. Furthermore, we have to copy our slice header to
+	// he already heap allocated upkErrPtr, which we can later reuse.
+	upkErr = upkErr[:n]
+	*upkErrPtr = upkErr
+	u.upkErr = upkErrPtr
+
+	return upkErr
+}
+
+// unpackTree unpacks err and all errors wrapped by it into upkErr, starting at
+// index i. The parent is the index of the element that wraps err, or -1 if err
+// is the root. prvStack is the stack of the closest ancestor that has a stack
+// trace. It returns the index of the next free element.
+func (u *Unpacker) unpackTree(upkErr UnpackedError, i, parent int, err error, prvStack Stack) int {
+	var (
+		goroutine  *Goroutine
+		suppressed []error
+	)
 	for err != nil {
+		// errors started by [Go] are merged into the error they wrap
+		if gerr, ok := err.(*goroutineErr); ok {
+			goroutine = gerr.Goroutine()
+			err = gerr.err
+			continue
+		}
+		// suppressed errors are attached to the error they wrap
+		if serr, ok := err.(*suppressedErr); ok {
+			suppressed = append(suppressed, serr.suppressed...)
+			err = serr.err
+			continue
+		}
+		// the errors wrapped by multiple %w verbs of [Errorf] are unpacked as
+		// if they were wrapped by the error directly
+		if werr, ok := err.(*wrapErrors); ok && parent >= 0 {
+			for _, child := range werr.errs {
+				i = u.unpackTree(upkErr, i, parent, child, prvStack)
+			}
+			return i
+		}
+		if gerr, ok := err.(goroutiner); ok {
+			if gr := gerr.Goroutine(); gr != nil {
+				goroutine = gr
+			}
+		}
+		upkElm := &upkErr[i]
+		upkElm.Goroutine = goroutine
+		goroutine = nil
+		if serr, ok := err.(suppressor); ok && len(serr.suppressedErrors()) > 0 {
+			if len(suppressed) == 0 {
+				suppressed = serr.suppressedErrors()
+			} else {
+				suppressed = append(suppressed, serr.suppressedErrors()...)
+			}
+		}
+		upkElm.Suppressed = suppressed
+		suppressed = nil
+		upkElm.Parent = parent
+		upkElm.Children = upkElm.Children[:0]
+		if parent >= 0 {
+			upkErr[parent].Children = append(upkErr[parent].Children, i)
+		}
+
 		// If the error provides a list of callers, we can use that to build a
 		// stack. This includes [*bruh.Err], but also other compatible errors.
 		if e, ok := err.(callerser); ok {
-			callers := stackPC(e.Callers())
-			stack := Stack(upkErr[i].stackStore[:])
-			stack = stack[:callers.toStack(stack)]
+			stack := upkElm.stackStore[:0]
+			if !u.pcOnly {
+				var pcs [maxStackCaptureDepth]uintptr
+				callers := stackPC(appendCallersOf(pcs[:0], e))
+				// inlined calls may result in more frames than program counters
+				if size := max(len(callers), DefaultErrorStackDepth); cap(upkElm.stackStore) < size {
+					upkElm.stackStore = make(Stack, size)
+				}
+				stack = upkElm.stackStore[:cap(upkElm.stackStore)]
+				stack = stack[:callers.toStack(stack)]
+			}
+			var message string
+			if m, ok := err.(messager); ok {
+				message = m.Message()
+			} else {
+				message = trimWrappedMessages(err.Error(), err)
+			}
+			upkElm.Err = err
+			upkElm.Msg = message
+			upkElm.Stack = stack
+			upkElm.PartialStack = stack.RelativeTo(prvStack)
+			// errors with a reduced or missing stack, see
+			// [SetStackCapturePolicy], are skipped
+			if len(stack) > 0 {
+				prvStack = stack
+			}
+		} else if e, ok := err.(framer); ok {
+			// error carries an already symbolized stack, e.g. a [*RemoteErr]
+			stack := e.Frames()
 			var message string
 			if m, ok := err.(messager); ok {
 				message = m.Message()
 			} else {
-				message = err.Error()
-				nerr := Unwrap(err)
-				if nerr != nil {
-					nextMessage := nerr.Error()
-					if strings.HasSuffix(message, nextMessage) {
-						message = message[:len(message)-len(nextMessage)]
-						message = strings.TrimSuffix(message, ": ")
+				message = trimWrappedMessages(err.Error(), err)
+			}
+			upkElm.Err = err
+			upkElm.Msg = message
+			upkElm.Stack = stack
+			upkElm.PartialStack = stack.RelativeTo(prvStack)
+			if len(stack) > 0 {
+				prvStack = stack
+			}
+		} else {
+			// external error without a stack
+			extErr := err
+			if !u.unpackAll {
+				// keep errors without a stack trace as is
+				for {
+					next, ok := err.(unwraper)
+					if !ok {
+						break
 					}
+					nerr := next.Unwrap()
+					if nerr == nil {
+						break
+					}
+					if _, isCallersErrorer := nerr.(callerser); isCallersErrorer {
+						break
+					}
+					if _, isFramer := nerr.(framer); isFramer {
+						break
+					}
+					if _, isMultiUnwraper := nerr.(multiUnwraper); isMultiUnwraper {
+						break
+					}
+					if _, isSuppressed := nerr.(*suppressedErr); isSuppressed {
+						break
+					}
+					err = nerr
 				}
 			}
-			upkErr[i].Err = err
-			upkErr[i].Msg = message
-			upkErr[i].Stack = stack
-			upkErr[i].PartialStack = stack.RelativeTo(prvStack)
-			prvStack = stack
-			err = Unwrap(err)
-			i++
-			continue
+			upkElm.Err = extErr
+			upkElm.Msg = trimWrappedMessages(extErr.Error(), err)
+			upkElm.Stack = Stack{}
+			upkElm.PartialStack = Stack{}
 		}
 
-		// external error without a stack
-		extErr := err
-		if u.unpackAll {
-			err = Unwrap(err)
-		} else {
-			// keep errors without a stack trace as is
-			for {
-				err = Unwrap(err)
-				if err == nil {
-					break
-				}
-				if _, isCallersErrorer := err.(callerser); isCallersErrorer {
-					break
+		parent = i
+		i++
+		switch uerr := err.(type) {
+		case unwraper:
+			err = uerr.Unwrap()
+		case multiUnwraper:
+			for _, child := range uerr.Unwrap() {
+				if child != nil {
+					i = u.unpackTree(upkErr, i, parent, child, prvStack)
 				}
 			}
+			return i
+		default:
+			return i
 		}
-		message := extErr.Error()
-		if err != nil {
-			nextMessage := err.Error()
-			if strings.HasSuffix(message, nextMessage) {
-				message = message[:len(message)-len(nextMessage)]
-				message = strings.TrimSuffix(message, ": ")
+	}
+	return i
+}
+
+// trimWrappedMessages removes the messages of the errors wrapped by err from
+// msg. For errors wrapping multiple errors, the message is only emptied if it
+// consists solely of the wrapped messages separated by newlines, as produced by
+// [errors.Join].
+func trimWrappedMessages(msg string, err error) string {
+	switch u := err.(type) {
+	case unwraper:
+		nerr := u.Unwrap()
+		if nerr == nil {
+			return msg
+		}
+		nextMessage := nerr.Error()
+		if strings.HasSuffix(msg, nextMessage) {
+			msg = msg[:len(msg)-len(nextMessage)]
+			msg = strings.TrimSuffix(msg, ": ")
+		}
+	case multiUnwraper:
+		rest := msg
+		first := true
+		for _, nerr := range u.Unwrap() {
+			if nerr == nil {
+				continue
+			}
+			if !first {
+				if !strings.HasPrefix(rest, "\n") {
+					return msg
+				}
+				rest = rest[1:]
+			}
+			first = false
+			nextMessage := nerr.Error()
+			if !strings.HasPrefix(rest, nextMessage) {
+				return msg
 			}
+			rest = rest[len(nextMessage):]
+		}
+		if rest == "" {
+			return ""
 		}
-		upkErr[i].Err = extErr
-		upkErr[i].Msg = message
-		upkErr[i].Stack = Stack{}
-		upkErr[i].PartialStack = Stack{}
-		i++
 	}
-
-	// At this point len(upkErr) == cap(upkErr), thus we have to trim the slice
-	// down to its actual size. Furthermore, we have to copy our slice header to
-	// he already heap allocated upkErrPtr, which we can later reuse.
-	upkErr = upkErr[:i]
-	*upkErrPtr = upkErr
-	u.upkErr = upkErrPtr
-
-	return upkErr
+	return msg
 }
 
 // CombinedStack returns a combined stack trace of all errors in the chain.
@@ -260,27 +410,29 @@ func disposeCombinedStackPC(stack *stackPC) {
 	combinedStackPCPool.Put(stack)
 }
 
-// combinedStack returns a combined stack trace of all errors in the chain.
+// combinedStack returns a combined stack trace of all errors in the chain. If
+// the chain branches into an error tree, only the first branch is followed.
 func combinedStack(err error, stack Stack) int {
 	chainLen := 0
-	for uerr := err; uerr != nil; uerr = Unwrap(uerr) {
+	for uerr := err; uerr != nil; uerr = unwrapFirst(uerr) {
+		if _, ok := uerr.(framer); ok {
+			return combinedFrames(err, stack)
+		}
 		chainLen++
 	}
 	errsPtr := newCallerserErrors(chainLen)
 	errs := *errsPtr
 
 	// unwrap the errors
-	for uerr := err; uerr != nil; uerr = Unwrap(uerr) {
+	var pcs [maxStackCaptureDepth]uintptr
+	for uerr := err; uerr != nil; uerr = unwrapFirst(uerr) {
 		if cerr, ok := uerr.(callerser); ok {
-			callers := cerr.Callers()
+			callers := appendCallersOf(pcs[:0], cerr)
 			if len(callers) == 0 {
 				continue
 			}
-			frames := allocFrames(callers)
-			frame, _ := frames.Next()
-			disposeFrames(frames)
 			// globally defined errors are no much use to use, so we stop here
-			if isGloballyDefinedError(frame.Function) {
+			if isGloballyDefinedErrorPC(callers[0]) {
 				break
 			}
 			errs = append(errs, cerr)
@@ -293,9 +445,9 @@ func combinedStack(err error, stack Stack) int {
 	// combine the stack traces
 	combinedPtr := newCombinedStackPC()
 	combined := *combinedPtr
-	combined = combined[:copy(combined, errs[len(errs)-1].Callers())]
+	combined = combined[:copy(combined, appendCallersOf(pcs[:0], errs[len(errs)-1]))]
 	for i := len(errs) - 2; i >= 0; i-- {
-		current := errs[i].Callers()
+		current := appendCallersOf(pcs[:0], errs[i])
 		relative := combined.relativeTo(current)
 		capacityLeft := cap(combined) - len(relative)
 		if capacityLeft-len(current) < 0 {
@@ -311,6 +463,48 @@ func combinedStack(err error, stack Stack) int {
 	return n
 }
 
+// appendCallersOf appends the stack of the given error to dst and returns the
+// extended slice. Unlike [Err.Callers], it doesn't allocate for errors with a
+// delta-encoded stack if dst is large enough.
+func appendCallersOf(dst []uintptr, err callerser) []uintptr {
+	if be, ok := err.(baseErrer); ok {
+		return be.baseErr().appendCallers(dst, 0)
+	}
+	return append(dst, err.Callers()...)
+}
+
+// combinedFrames is like [combinedStack], but also supports errors that carry
+// an already symbolized stack (see [framer]). It is slower than
+// [combinedStack], because the stacks have to be symbolized before they can be
+// combined.
+func combinedFrames(err error, stack Stack) int {
+	var stacks []Stack
+	for uerr := err; uerr != nil; uerr = unwrapFirst(uerr) {
+		var current Stack
+		switch e := uerr.(type) {
+		case callerser:
+			callers := stackPC(e.Callers())
+			current = make(Stack, max(len(callers), DefaultErrorStackDepth))
+			current = current[:callers.toStack(current)]
+		case framer:
+			current = e.Frames()
+		}
+		if len(current) > 0 {
+			stacks = append(stacks, current)
+		}
+	}
+	if len(stacks) == 0 {
+		return 0
+	}
+
+	// combine the stack traces
+	combined := append(Stack(nil), stacks[len(stacks)-1]...)
+	for i := len(stacks) - 2; i >= 0; i-- {
+		combined = append(combined.RelativeTo(stacks[i]), stacks[i]...)
+	}
+	return copy(stack, combined)
+}
+
 // -----------------------------------------------------------------------------
 
 // UnpackedElement represents a single error frame and the accompanying message.
@@ -319,18 +513,37 @@ type UnpackedElement struct {
 	Err error
 	// Msg is the message contained in the error.
 	Msg string
-	// stackStore is the backing store for Stack and PartialStack.
-	stackStore [MaxErrorStackDepth]StackFrame
+	// stackStore is the backing store for Stack and PartialStack. It is grown
+	// as needed and reused when the element is reused.
+	stackStore Stack
 	// Stack is the error stack for this particular error instance.
 	Stack Stack
 	// PartialStack is the error stack with parts cut off that are already in
 	// the previous error stack.
 	PartialStack Stack
+	// Parent is the index of the element that wraps this error. It is -1 for
+	// the root of the error tree.
+	Parent int
+	// Children are the indices of the elements that are wrapped by this error.
+	// Errors that implement `Unwrap() []error` can have more than one child.
+	Children []int
+	// Goroutine is the goroutine the error occurred in, if known. It is set for
+	// errors returned by goroutines started with [Go] and for errors that
+	// originate from a parsed [Panic].
+	Goroutine *Goroutine
+	// Suppressed are the errors that were suppressed by this error, see
+	// [AddSuppressed]. They aren't part of the unpacked error tree and can be
+	// formatted on their own.
+	Suppressed []error
 }
 
 // UnpackedError represents an unpacked error which is quite useful for
 // formatting purposes and other error processing. Use [Unpack] to unpack any
 // kind of error that supports it.
+//
+// The elements are stored in depth-first order. For a plain error chain, each
+// element wraps the next one. For error trees, use [UnpackedElement.Parent]
+// and [UnpackedElement.Children] to navigate between the elements.
 type UnpackedError []UnpackedElement
 
 var unpackedErrorPool = sync.Pool{
@@ -355,18 +568,44 @@ func disposeUnpackedError(upkErr *UnpackedError) {
 	unpackedErrorPool.Put(upkErr)
 }
 
-// CombinedStack returns a combined stack trace of all errors in the chain.
+// CombinedStack returns a combined stack trace of all errors in the chain. If
+// the chain branches into an error tree, only the first branch is followed.
 func (upkErr UnpackedError) CombinedStack() Stack {
 	if len(upkErr) == 0 {
 		return Stack{}
 	}
 	numFrames := 0
-	for i := range upkErr {
+	last := 0
+	for i := 0; ; i = upkErr[i].Children[0] {
 		numFrames += len(upkErr[i].PartialStack)
+		last = i
+		if len(upkErr[i].Children) == 0 {
+			break
+		}
 	}
 	combinedStack := make(Stack, 0, numFrames)
-	for i := len(upkErr) - 1; i >= 0; i-- {
+	for i := last; i >= 0; i = upkErr[i].Parent {
 		combinedStack = append(combinedStack, upkErr[i].PartialStack...)
 	}
 	return combinedStack
 }
+
+// BranchLevel returns the number of ancestors of the element at index i that
+// wrap more than one error. It is zero for all elements of a plain error chain
+// and can be used by formatters to indent the branches of an error tree.
+func (upkErr UnpackedError) BranchLevel(i int) int {
+	level := 0
+	for p := upkErr[i].Parent; p >= 0; p = upkErr[p].Parent {
+		if len(upkErr[p].Children) > 1 {
+			level++
+		}
+	}
+	return level
+}
+
+// IsBranch reports whether the element at index i is one of multiple errors
+// wrapped by its parent.
+func (upkErr UnpackedError) IsBranch(i int) bool {
+	p := upkErr[i].Parent
+	return p >= 0 && len(upkErr[p].Children) > 1
+}
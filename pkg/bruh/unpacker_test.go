@@ -65,6 +65,12 @@ func TestUnpack(t *testing.T) {
 		true,
 		bruh.UnpackedError{{Msg: "external wrapper"}, {Msg: "some external error"}, {Msg: "root cause"}},
 	)
+	assertUnpack(
+		"ErrorfWrapVerb",
+		bruh.Errorf("%w: while reading %s", bruh.Errorf("open %s: %w", "file", errors.New("root cause")), "file"),
+		false,
+		bruh.UnpackedError{{Msg: "while reading file"}, {Msg: "open file"}, {Msg: "root cause"}},
+	)
 	assertUnpack(
 		"WrappedMixedError",
 		bruh.Wrap(
@@ -81,6 +87,102 @@ func TestUnpack(t *testing.T) {
 	)
 }
 
+func TestUnpackTree(t *testing.T) {
+	t.Parallel()
+	type node struct {
+		msg      string
+		parent   int
+		children []int
+		branch   int
+	}
+	assertUnpackTree := func(name string, err error, unpackAll bool, exp []node) {
+		t.Run(name, func(t *testing.T) {
+			formatter := func(b []byte, unpacker *bruh.Unpacker) []byte {
+				upkErr := unpacker.Unpack()
+				if len(upkErr) != len(exp) {
+					t.Fatalf("expected %d elements, got %d", len(exp), len(upkErr))
+				}
+				for i, e := range exp {
+					act := node{
+						msg:      upkErr[i].Msg,
+						parent:   upkErr[i].Parent,
+						children: upkErr[i].Children,
+						branch:   upkErr.BranchLevel(i),
+					}
+					if fmt.Sprint(e) != fmt.Sprint(act) {
+						t.Errorf("element %d: expected %+v, got %+v", i, e, act)
+					}
+				}
+				return b
+			}
+
+			bruh.StringFormat(err, formatter, unpackAll)
+		})
+	}
+
+	assertUnpackTree(
+		"Chain",
+		bruh.Wrap(bruh.New("root error"), "context"),
+		false,
+		[]node{{"context", -1, []int{1}, 0}, {"root error", 0, []int{}, 0}},
+	)
+	assertUnpackTree(
+		"Join",
+		bruh.Wrap(bruh.Join(bruh.Wrap(bruh.New("a"), "wrapped a"), errors.New("b")), "joined"),
+		false,
+		[]node{
+			{"joined", -1, []int{1}, 0},
+			{"", 0, []int{2, 4}, 0},
+			{"wrapped a", 1, []int{3}, 1},
+			{"a", 2, []int{}, 1},
+			{"b", 1, []int{}, 1},
+		},
+	)
+	assertUnpackTree(
+		"ErrorsJoin",
+		errors.Join(bruh.New("a"), fmt.Errorf("wrapped b: %w", errors.New("b"))),
+		false,
+		[]node{
+			{"", -1, []int{1, 2}, 0},
+			{"a", 0, []int{}, 1},
+			{"wrapped b: b", 0, []int{}, 1},
+		},
+	)
+	assertUnpackTree(
+		"ErrorsJoinUnpackAll",
+		errors.Join(bruh.New("a"), fmt.Errorf("wrapped b: %w", errors.New("b"))),
+		true,
+		[]node{
+			{"", -1, []int{1, 2}, 0},
+			{"a", 0, []int{}, 1},
+			{"wrapped b", 0, []int{3}, 1},
+			{"b", 2, []int{}, 1},
+		},
+	)
+	assertUnpackTree(
+		"ErrorfMultipleWrapVerbs",
+		bruh.Errorf("reading: %w, closing: %w", bruh.New("a"), errors.New("b")),
+		false,
+		[]node{
+			{"reading, closing", -1, []int{1, 2}, 0},
+			{"a", 0, []int{}, 1},
+			{"b", 0, []int{}, 1},
+		},
+	)
+	assertUnpackTree(
+		"NestedJoin",
+		bruh.Join(bruh.New("a"), bruh.Join(bruh.New("b"), bruh.New("c"))),
+		false,
+		[]node{
+			{"", -1, []int{1, 2}, 0},
+			{"a", 0, []int{}, 1},
+			{"", 0, []int{3, 4}, 1},
+			{"b", 2, []int{}, 2},
+			{"c", 2, []int{}, 2},
+		},
+	)
+}
+
 func isUnpackedErrorEqual(a, b []bruh.UnpackedElement) bool {
 	// If one is nil, the other must also be nil.
 	if (a == nil) != (b == nil) {
@@ -0,0 +1,223 @@
+package bruh
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// wrapVerb is a %w verb of a format string whose argument is an error.
+type wrapVerb struct {
+	// start and end are the positions of the verb, including its flags, in
+	// the format string.
+	start, end int
+	// arg is the index of the argument of the verb.
+	arg int
+}
+
+// formatMessage returns the errors referenced by the %w verbs of the format
+// string and the lazy message of an error created by [Errorf]. Like
+// [fmt.Errorf], the referenced errors are wrapped. The message of the error
+// itself consists only of the segment that belongs to it, i.e. the format
+// string without the %w verbs and the ": " separators next to them, so that
+// the messages of the wrapped errors aren't repeated. If the format string
+// ends with a single %w verb, the full message is built the same way as for
+// [Wrapf]. Otherwise, the messages of the wrapped errors are embedded in the
+// full message, as it is rendered by [fmt.Errorf].
+func formatMessage(format string, args []any) ([]error, *lazyMessage) {
+	verbs, reordered := parseWrapVerbs(format, args)
+	if len(verbs) == 0 {
+		return nil, &lazyMessage{format: format, args: args}
+	}
+
+	errs := make([]error, 0, len(verbs))
+	wrappedArgs := make([]int, 0, len(verbs))
+	for _, v := range verbs {
+		if !slices.Contains(wrappedArgs, v.arg) {
+			wrappedArgs = append(wrappedArgs, v.arg)
+			errs = append(errs, args[v.arg].(error)) //nolint:revive
+		}
+	}
+
+	msg := &lazyMessage{format: segmentFormat(format, verbs), args: args}
+	if !reordered {
+		// the arguments of the removed verbs would be reported as extra
+		// arguments, unless they are referenced by explicit indexes
+		msg.args = make([]any, 0, len(args)-len(wrappedArgs))
+		for i, arg := range args {
+			if !slices.Contains(wrappedArgs, i) {
+				msg.args = append(msg.args, arg)
+			}
+		}
+	}
+
+	last := verbs[len(verbs)-1]
+	if len(verbs) == 1 && last.end == len(format) && format[last.start:last.end] == "%w" &&
+		(last.start == 0 || strings.HasSuffix(format[:last.start], ": ")) {
+		return errs, msg
+	}
+
+	// The full message is rendered from the original format string, where the
+	// %w verbs are replaced by %v verbs with the same flags.
+	full := []byte(format)
+	for _, v := range verbs {
+		full[v.end-1] = 'v'
+	}
+	msg.fullFormat = string(full)
+	msg.fullArgs = args
+	return errs, msg
+}
+
+// segmentFormat returns the format string without the given verbs, the ": "
+// separators that precede or follow them and the separators between them, e.g.
+// ", " in "%w, %w". The separators next to a verb at the start or end of the
+// format string are removed as well, e.g. the space in "%w happened".
+func segmentFormat(format string, verbs []wrapVerb) string {
+	var sb strings.Builder
+	sb.Grow(len(format))
+	last := 0
+	for i, v := range verbs {
+		segment := format[last:v.start]
+		last = v.end
+		if i > 0 && strings.Trim(segment, wrapVerbSeparators) == "" {
+			segment = ""
+		}
+		if strings.HasSuffix(segment, ": ") {
+			segment = segment[:len(segment)-2]
+		} else if strings.HasPrefix(format[v.end:], ": ") {
+			last += 2
+		}
+		sb.WriteString(segment)
+	}
+	sb.WriteString(format[last:])
+	segmented := sb.String()
+	if verbs[0].start == 0 {
+		segmented = strings.TrimLeft(segmented, wrapVerbSeparators)
+	}
+	if verbs[len(verbs)-1].end == len(format) {
+		segmented = strings.TrimRight(segmented, wrapVerbSeparators)
+	}
+	return segmented
+}
+
+// wrapVerbSeparators are the characters that separate %w verbs from the rest
+// of the format string.
+const wrapVerbSeparators = " ,;:\n"
+
+// parseWrapVerbs returns the %w verbs of the format string whose arguments are
+// non-nil errors and whether the format string uses explicit argument indexes.
+// It parses the format string the same way as [fmt.Sprintf].
+func parseWrapVerbs(format string, args []any) (verbs []wrapVerb, reordered bool) {
+	// fast path for the majority of format strings
+	if strings.IndexByte(format, 'w') < 0 {
+		return nil, false
+	}
+	argNum := 0
+	for i := 0; i < len(format); {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+		start := i
+		i++
+		for i < len(format) && strings.IndexByte("#0+- ", format[i]) >= 0 {
+			i++
+		}
+		// width
+		i, argNum, reordered = parseArgIndex(format, i, argNum, reordered)
+		if i < len(format) && format[i] == '*' {
+			i++
+			argNum++
+		} else {
+			i = skipDigits(format, i)
+		}
+		// precision
+		if i < len(format) && format[i] == '.' {
+			i++
+			i, argNum, reordered = parseArgIndex(format, i, argNum, reordered)
+			if i < len(format) && format[i] == '*' {
+				i++
+				argNum++
+			} else {
+				i = skipDigits(format, i)
+			}
+		}
+		i, argNum, reordered = parseArgIndex(format, i, argNum, reordered)
+		if i >= len(format) {
+			break
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size
+		switch verb {
+		case '%':
+			// a literal percent sign doesn't consume an argument
+		case 'w':
+			if argNum < len(args) {
+				if err, ok := args[argNum].(error); ok && err != nil {
+					verbs = append(verbs, wrapVerb{start: start, end: i, arg: argNum})
+				}
+			}
+			argNum++
+		default:
+			argNum++
+		}
+	}
+	return verbs, reordered
+}
+
+// parseArgIndex parses an explicit argument index, e.g. [2], at position i of
+// the format string. It returns the position after the index, the index of the
+// next argument and whether the format string uses explicit indexes.
+func parseArgIndex(format string, i, argNum int, reordered bool) (int, int, bool) {
+	if i >= len(format) || format[i] != '[' {
+		return i, argNum, reordered
+	}
+	end := strings.IndexByte(format[i:], ']')
+	if end < 0 {
+		return i, argNum, reordered
+	}
+	n := 0
+	for _, c := range format[i+1 : i+end] {
+		if c < '0' || c > '9' {
+			return i + end + 1, argNum, true
+		}
+		n = n*10 + int(c-'0')
+	}
+	if n >= 1 {
+		argNum = n - 1
+	}
+	return i + end + 1, argNum, true
+}
+
+// skipDigits returns the position of the first non-digit character at or after
+// position i of the format string.
+func skipDigits(format string, i int) int {
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+// -----------------------------------------------------------------------------
+
+// wrapErrors holds the errors referenced by multiple %w verbs of [Errorf]. The
+// [Unpacker] skips it, so that the errors appear as if they were wrapped by the
+// error created by [Errorf] directly.
+type wrapErrors struct { //nolint: errname
+	errs []error
+}
+
+// Error returns the messages of the wrapped errors, separated by newlines.
+func (e *wrapErrors) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the wrapped errors.
+func (e *wrapErrors) Unwrap() []error {
+	return e.errs
+}
@@ -11,7 +11,8 @@
 package ctxerror
 
 import (
-	"fmt"
+	"crypto/sha256"
+	"encoding/hex"
 	"maps"
 
 	"github.com/aisbergg/go-bruh/pkg/bruh"
@@ -41,6 +42,8 @@ type ModifiableContextErr interface {
 	SetContexts(context Context) ModifiableContextErr
 	SetTag(key, value string) ModifiableContextErr
 	SetTags(tags Tags) ModifiableContextErr
+	SetKind(kind bruh.Kind) ModifiableContextErr
+	SetFingerprint(parts ...string) ModifiableContextErr
 	Unshare() ModifiableContextErr
 }
 
@@ -68,6 +71,10 @@ type Err struct { //nolint: errname
 	bruh.Err
 	context Context
 	tags    Tags
+	kind    bruh.Kind
+	// fingerprint overrides the type name and message of the error when the
+	// fingerprint of the chain is computed.
+	fingerprint []string
 
 	// shared indicates whether this error uses shared metadata maps found
 	// earlier in the chain. Default is true. When set to false the error will
@@ -88,7 +95,7 @@ func New(msg string) ModifiableContextErr {
 
 // Errorf creates a new context-aware error [Err] with a formatted message.
 func Errorf(format string, args ...any) ModifiableContextErr {
-	return &Err{Err: *bruh.NewSkip(1, fmt.Sprintf(format, args...)), shared: true}
+	return &Err{Err: *bruh.ErrorfSkip(1, format, args...), shared: true}
 }
 
 // Wrap wraps the given error by creating new context-aware error [Err] with
@@ -106,7 +113,7 @@ func Wrapf(err error, format string, args ...any) ModifiableContextErr {
 	if err == nil {
 		return nil
 	}
-	return &Err{Err: *bruh.WrapSkip(err, 1, fmt.Sprintf(format, args...)), shared: true}
+	return &Err{Err: *bruh.WrapfSkip(err, 1, format, args...), shared: true}
 }
 
 // SetContext adds the given key-value pairs to the context of the error.
@@ -177,6 +184,40 @@ func (e *Err) SetTags(tags Tags) ModifiableContextErr {
 	return e
 }
 
+// SetKind sets the kind of the error, which classifies the error chain, see
+// [bruh.KindOf].
+func (e *Err) SetKind(kind bruh.Kind) ModifiableContextErr {
+	if e == nil {
+		return nil
+	}
+	e.kind = kind
+	return e
+}
+
+// Kind returns the kind of the error, not considering wrapped errors. Use
+// [bruh.KindOf] to get the kind of an error chain.
+func (e *Err) Kind() bruh.Kind {
+	return e.kind
+}
+
+// SetFingerprint sets custom fingerprint parts of the error. They are used
+// instead of the type name and message of the error when the fingerprint of
+// the chain is computed, see [bruh.Fingerprint]. This is useful to group
+// errors whose messages contain variable data, like IDs.
+func (e *Err) SetFingerprint(parts ...string) ModifiableContextErr {
+	if e == nil {
+		return nil
+	}
+	e.fingerprint = parts
+	return e
+}
+
+// FingerprintParts returns the custom fingerprint parts of the error or nil if
+// none were set.
+func (e *Err) FingerprintParts() []string {
+	return e.fingerprint
+}
+
 // Unshare makes the error keep its own private copies of context and tags.
 // Call this when you plan to make an error global or reuse it across
 // independent wrappers. It deep-copies the maps and marks the error as
@@ -382,7 +423,7 @@ func GetContext(err error) Context {
 	}
 
 	// merge other context maps into the common one
-	for uerr := bruh.Unwrap(err); uerr != nil && depthToUnwrap > 0; uerr = bruh.Unwrap(uerr) {
+	for uerr := err; uerr != nil && depthToUnwrap > 0; uerr = bruh.Unwrap(uerr) {
 		depthToUnwrap--
 		if terr, ok := uerr.(contextAppender); ok {
 			terr.AppendContext(ctx)
@@ -434,7 +475,7 @@ func GetTags(err error) Tags {
 	}
 
 	// merge other tags maps into the common one
-	for uerr := bruh.Unwrap(err); uerr != nil && depthToUnwrap > 0; uerr = bruh.Unwrap(uerr) {
+	for uerr := err; uerr != nil && depthToUnwrap > 0; uerr = bruh.Unwrap(uerr) {
 		depthToUnwrap--
 		if terr, ok := uerr.(tagsAppender); ok {
 			terr.AppendTags(tags)
@@ -446,6 +487,104 @@ func GetTags(err error) Tags {
 	return tags
 }
 
+// GetFingerprint returns the fingerprint of the given error chain, which can be
+// used by exporters to group and deduplicate errors. If an error of the chain
+// has custom fingerprint parts set by [Err.SetFingerprint], the fingerprint is
+// built from the parts of the outermost such error only, so that the errors are
+// grouped by the parts regardless of their stacks. Otherwise, [bruh.Fingerprint]
+// is returned. If err is nil, an empty string is returned.
+func GetFingerprint(err error, opts ...bruh.FingerprintOptions) string {
+	for uerr := err; uerr != nil; uerr = bruh.Unwrap(uerr) {
+		cerr, ok := uerr.(*Err)
+		if !ok || cerr.fingerprint == nil {
+			continue
+		}
+		h := sha256.New()
+		for _, part := range cerr.fingerprint {
+			_, _ = h.Write([]byte(part))
+			_, _ = h.Write([]byte{0})
+		}
+		var sum [sha256.Size]byte
+		return hex.EncodeToString(h.Sum(sum[:0])[:16])
+	}
+	return bruh.Fingerprint(err, opts...)
+}
+
+// NewReport creates a serializable [bruh.Report] of the given error chain,
+// including its tags and context. The tags and context are available again
+// through [GetTags] and [GetContext] on the error returned by
+// [bruh.Report.Err]. If err is nil, nil is returned.
+func NewReport(err error) *bruh.Report {
+	report := bruh.NewReport(err)
+	if report == nil {
+		return nil
+	}
+	if tags := GetTags(err); len(tags) > 0 {
+		report.Tags = maps.Clone(tags)
+	}
+	if ctx := GetContext(err); len(ctx) > 0 {
+		report.Context = make(Context, len(ctx))
+		for g, m := range ctx {
+			report.Context[g] = maps.Clone(m)
+		}
+	}
+	return report
+}
+
+// Fields returns the fields of the given error chain as a flat map, including
+// its tags and context, using the field names of the mapping. See [bruh.Fields]
+// for details. The tags are keyed by [bruh.FieldMapping.TagsPrefix] and the
+// tag key, the context values by [bruh.FieldMapping.ContextPrefix], the group
+// and the key, e.g. `user.id`. Tags and context values whose keys collide with
+// the fields of the error itself, e.g. `exception.message` with
+// [bruh.OTelFieldMapping], are left out. If err is nil, nil is returned.
+//
+// Example usage:
+//
+//	fields := ctxerror.Fields(err, bruh.ECSFieldMapping, bruh.JavaStackTraceFormatter)
+func Fields(err error, mapping bruh.FieldMapping, f bruh.Formatter) map[string]any {
+	errFields := bruh.Fields(err, mapping, f)
+	if errFields == nil {
+		return nil
+	}
+	tags := GetTags(err)
+	ctx := GetContext(err)
+	size := len(errFields) + len(tags)
+	for _, m := range ctx {
+		size += len(m)
+	}
+	fields := make(map[string]any, size)
+	for k, v := range tags {
+		fields[mapping.TagsPrefix+k] = v
+	}
+	for g, m := range ctx {
+		for k, v := range m {
+			fields[mapping.ContextPrefix+g+"."+k] = v
+		}
+	}
+	// the fields of the error are written last, so that they win over
+	// colliding tags and context values
+	maps.Copy(fields, errFields)
+	return fields
+}
+
+// NewFieldsFormatter returns a [bruh.Formatter] that produces the fields of the
+// error chain, including its tags and context (see [Fields]), as a single-line
+// JSON object. The stack trace is produced by f.
+//
+// Example usage:
+//
+//	f := ctxerror.NewFieldsFormatter(bruh.ECSFieldMapping, bruh.JavaStackTraceFormatter)
+//	fmt.Println(bruh.StringFormat(err, f))
+func NewFieldsFormatter(mapping bruh.FieldMapping, f bruh.Formatter) bruh.Formatter {
+	return func(b []byte, unpacker *bruh.Unpacker) []byte {
+		if unpacker.Error() == nil {
+			return b
+		}
+		return bruh.AppendFieldsJSON(b, Fields(unpacker.Error(), mapping, f))
+	}
+}
+
 type contexter interface {
 	Context() Context
 }
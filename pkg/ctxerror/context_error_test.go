@@ -1,7 +1,9 @@
 package ctxerror
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -58,6 +60,13 @@ func TestConstructors(t *testing.T) {
 	assertConstructor("Wrapf", func() error { return Wrapf(root, "outer=%d", 7) }, "outer=7: root")
 	assertNilConstructor("WrapNilReturnsNil", func() error { return Wrap(nil, "x") })
 	assertNilConstructor("WrapfNilReturnsNil", func() error { return Wrapf(nil, "x=%d", 1) })
+
+	t.Run("ErrorfWrapsErrorsReferencedByWrapVerbs", func(t *testing.T) {
+		err := Errorf("outer=%d: %w", 7, root).SetTag("region", "us-west")
+		require.Equal("outer=7: root", err.Error())
+		require.Equal("outer=7", err.(*Err).Message())
+		require.True(errors.Is(err, root), "expected the error to wrap root")
+	})
 }
 
 // -----------------------------------------------------------------------------
@@ -73,9 +82,72 @@ func TestNilReceiverModifiers(t *testing.T) {
 	assert.Nil(e.SetContexts(Context{"group": {"k": "v"}}))
 	assert.Nil(e.SetTag("k", "v"))
 	assert.Nil(e.SetTags(Tags{"k": "v"}))
+	assert.Nil(e.SetKind(bruh.KindNotFound))
+	assert.Nil(e.SetFingerprint("part"))
 	assert.Nil(e.Unshare())
 }
 
+// -----------------------------------------------------------------------------
+// SetKind
+// -----------------------------------------------------------------------------
+
+func TestSetKind(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	e := New("x").SetKind(bruh.KindNotFound)
+	assert.Equal(bruh.KindNotFound, bruh.KindOf(e))
+	assert.Equal(bruh.KindNotFound, bruh.KindOf(bruh.Wrap(e, "wrapped")))
+	assert.Equal(bruh.KindConflict, bruh.KindOf(Wrap(e, "wrapped").SetKind(bruh.KindConflict)))
+	assert.Equal(bruh.KindUnknown, bruh.KindOf(New("x")))
+}
+
+// -----------------------------------------------------------------------------
+// SetFingerprint
+// -----------------------------------------------------------------------------
+
+func TestSetFingerprint(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	newErr := func(id int, parts ...string) error {
+		e := New(fmt.Sprintf("user %d not found", id))
+		if len(parts) > 0 {
+			e.SetFingerprint(parts...)
+		}
+		return e
+	}
+	assert.True(bruh.Fingerprint(newErr(1)) != bruh.Fingerprint(newErr(2)))
+	// formatted messages are fingerprinted by their template
+	fps := make([]string, 0, 2)
+	for id := range 2 {
+		fps = append(fps, bruh.Fingerprint(Errorf("user %d not found", id)))
+	}
+	assert.Equal(fps[0], fps[1])
+	assert.Equal(bruh.Fingerprint(newErr(1, "user not found")), bruh.Fingerprint(newErr(2, "user not found")))
+	assert.True(bruh.Fingerprint(newErr(1, "a")) != bruh.Fingerprint(newErr(1, "b")))
+	assert.Equal([]string{"a", "b"}, New("x").SetFingerprint("a", "b").(*Err).FingerprintParts())
+	assert.Equal("", bruh.Fingerprint(nil))
+}
+
+func TestGetFingerprint(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	assert.Equal("", GetFingerprint(nil))
+	e := New("failed")
+	assert.Equal(bruh.Fingerprint(e), GetFingerprint(e))
+
+	// custom parts are preferred, regardless of the stacks of the errors
+	a := Wrap(New("a").SetFingerprint("database", "timeout"), "wrapped")
+	b := New("b").SetFingerprint("database", "timeout")
+	assert.Equal(GetFingerprint(a), GetFingerprint(b))
+	assert.Len(GetFingerprint(a), 32)
+	assert.True(GetFingerprint(a) != GetFingerprint(New("c").SetFingerprint("database")))
+	// the parts of the outermost error win
+	assert.Equal(GetFingerprint(New("c").SetFingerprint("outer")), GetFingerprint(Wrap(b, "x").SetFingerprint("outer")))
+}
+
 // -----------------------------------------------------------------------------
 // SetContext / SetContexts
 // -----------------------------------------------------------------------------
@@ -406,6 +478,29 @@ func TestGetContextAndTagsWithForeignError(t *testing.T) {
 	})
 }
 
+// foreignWrapper is a test helper that implements contexter and tagser and
+// wraps another error.
+type foreignWrapper struct{ foreignContexter }
+
+func (d foreignWrapper) Unwrap() error { return d.err }
+
+// Regression test: the merge loops of GetContext and GetTags started at the
+// error wrapped by the outermost one, so the context and tags of an outermost
+// foreign error were lost whenever a ctxerror.Err further down the chain held
+// the initialized maps.
+func TestGetContextAndTagsWithOutermostForeignError(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	inner := New("inner").
+		SetContext("req", map[string]any{"id": "1"}).
+		SetTag("region", "eu")
+	outer := foreignWrapper{foreignContexter{err: inner}}
+
+	assert.Equal(Context{"req": {"id": "1"}, "foreign": {"contexter": "yes"}}, GetContext(outer))
+	assert.Equal(Tags{"region": "eu", "foreign": "tagser"}, GetTags(outer))
+}
+
 func TestGetContextAndTagsWithForeignErrorThroughBruhWrap(t *testing.T) {
 	t.Parallel()
 	assert := testutils.NewAssert(t)
@@ -459,3 +554,74 @@ func TestGetContextAndTagsWithForeignErrorThroughBruhWrap(t *testing.T) {
 		assert.Equal(Tags{"k": "v", "foreign": "dumper"}, got)
 	})
 }
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.True(NewReport(nil) == nil)
+	})
+
+	t.Run("RoundTripKeepsContextAndTags", func(t *testing.T) {
+		inner := mustErr(t, New("inner")).
+			SetTag("region", "eu").
+			SetContext("req", map[string]any{"id": "1"})
+		outer := mustErr(t, Wrap(inner, "outer")).SetTag("op", "read")
+
+		data, err := json.Marshal(NewReport(outer))
+		assert.NoError(err)
+		report, err := bruh.UnmarshalReport(data)
+		assert.NoError(err)
+		remote := report.Err()
+		assert.Equal("outer: inner", remote.Error())
+		assert.Equal(Tags{"region": "eu", "op": "read"}, GetTags(remote))
+		assert.Equal(Context{"req": {"id": "1"}}, GetContext(remote))
+	})
+
+	t.Run("RemoteErrIsWrappable", func(t *testing.T) {
+		inner := mustErr(t, New("inner")).SetTag("region", "eu")
+		remote := NewReport(inner).Err()
+		outer := mustErr(t, Wrap(remote, "outer")).SetTag("op", "read")
+		assert.Equal(Tags{"region": "eu", "op": "read"}, GetTags(outer))
+	})
+}
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	assert.True(Fields(nil, bruh.ECSFieldMapping, nil) == nil)
+
+	inner := mustErr(t, New("inner")).
+		SetTag("region", "eu").
+		SetContext("user", map[string]any{"id": 42})
+	outer := mustErr(t, Wrap(inner, "outer")).SetTag("op", "read")
+
+	fields := Fields(outer, bruh.ECSFieldMapping, bruh.JavaStackTraceFormatter)
+	assert.Equal("outer: inner", fields["error.message"])
+	assert.Equal("eu", fields["labels.region"])
+	assert.Equal("read", fields["labels.op"])
+	assert.Equal(42, fields["user.id"])
+
+	fields = Fields(outer, bruh.OTelFieldMapping, bruh.JavaStackTraceFormatter)
+	assert.Equal("outer: inner", fields["exception.message"])
+	assert.Equal("eu", fields["region"])
+	assert.Equal(42, fields["user.id"])
+
+	// the fields of the error win over colliding tags and context values
+	colliding := mustErr(t, New("failed")).
+		SetTag("exception.message", "tag").
+		SetContext("error", map[string]any{"message": "context", "id": 7})
+	fields = Fields(colliding, bruh.OTelFieldMapping, nil)
+	assert.Equal("failed", fields["exception.message"])
+	fields = Fields(colliding, bruh.ECSFieldMapping, nil)
+	assert.Equal("failed", fields["error.message"])
+	assert.Equal(7, fields["error.id"])
+
+	out := bruh.StringFormat(outer, NewFieldsFormatter(bruh.ECSFieldMapping, bruh.JavaStackTraceFormatter))
+	var got map[string]any
+	assert.NoError(json.Unmarshal([]byte(out), &got))
+	assert.Equal("eu", got["labels.region"])
+	assert.Equal(float64(42), got["user.id"])
+}
@@ -3,23 +3,29 @@ package ctxotel
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/aisbergg/go-bruh/pkg/bruh"
 	"github.com/aisbergg/go-bruh/pkg/ctxerror"
 )
 
 // AsAttributes converts an error (with ctxerror context/tags) into a slice of
 // OTEL attribute key/value pairs. The error message is stored under the
-// "error" key. Context groups are flattened using dot notation (group.key).
+// "error" key and its fingerprint under the "error.fingerprint" key, see
+// [ctxerror.GetFingerprint]. Context groups are flattened using dot notation (group.key). If
+// the message is formatted, its template is stored under the "error.template"
+// key and its arguments under the "error.args.<name>" keys, where unnamed
+// arguments are named by their index, see [bruh.MessageTemplateOf].
 func AsAttributes(err error) []attribute.KeyValue {
 	if err == nil {
 		return []attribute.KeyValue{}
 	}
 
 	// estimate capacity
-	attrsSizeGuess := 1
+	attrsSizeGuess := 2
 	ctx := ctxerror.GetContext(err)
 	for _, m := range ctx {
 		attrsSizeGuess += len(m)
@@ -31,6 +37,17 @@ func AsAttributes(err error) []attribute.KeyValue {
 
 	attrs := make([]attribute.KeyValue, 0, attrsSizeGuess)
 	attrs = append(attrs, attribute.String("error", err.Error()))
+	attrs = append(attrs, attribute.String("error.fingerprint", ctxerror.GetFingerprint(err)))
+	if tmpl := bruh.MessageTemplateOf(err); len(tmpl.Args) > 0 {
+		attrs = append(attrs, attribute.String("error.template", tmpl.Template))
+		for i, arg := range tmpl.Args {
+			key := "error.args." + arg.Name
+			if arg.Name == "" {
+				key = "error.args." + strconv.Itoa(i)
+			}
+			attrs = toOTELAttributesRec(arg.Value, key, attrs)
+		}
+	}
 
 	for group, m := range ctx {
 		for k, v := range m {
@@ -8,6 +8,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
 	"github.com/aisbergg/go-bruh/pkg/ctxerror"
 	"github.com/aisbergg/go-bruh/pkg/ctxerror/ctxotel"
 )
@@ -93,3 +94,29 @@ func TestAsAttributes(t *testing.T) {
 		assert.Equal(v, got)
 	}
 }
+
+func TestAsAttributesFingerprint(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	err := ctxerror.New("boom")
+	attrs := attrsByKey(ctxotel.AsAttributes(err))
+	assert.Equal(bruh.Fingerprint(err), attrs["error.fingerprint"].AsString())
+
+	err = ctxerror.New("boom").SetFingerprint("custom")
+	attrs = attrsByKey(ctxotel.AsAttributes(err))
+	assert.Equal(ctxerror.GetFingerprint(err), attrs["error.fingerprint"].AsString())
+	assert.True(bruh.Fingerprint(err) != attrs["error.fingerprint"].AsString())
+}
+
+func TestAsAttributesMessageTemplate(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	err := ctxerror.Errorf("user %s not found after %d attempts", bruh.Arg("user_id", "u1"), 3)
+	attrs := attrsByKey(ctxotel.AsAttributes(err))
+
+	assert.Equal("user %s not found after %d attempts", attrs["error.template"].AsString())
+	assert.Equal("u1", attrs["error.args.user_id"].AsString())
+	assert.Equal(int64(3), attrs["error.args.1"].AsInt64())
+}
@@ -4,6 +4,7 @@ package ctxslog
 import (
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 	"unsafe"
@@ -15,9 +16,13 @@ import (
 // AsAttributes turns the given error into a slice of slog.Attr, which then can
 // be used with slog's LogAttrs method.
 //
-// The error message is included under the "error" key, and context and tags are
-// included as additional attributes. If the error implements slog.LogValuer,
-// its LogValue is also included.
+// The error message is included under the "error" key, its fingerprint under
+// the "error.fingerprint" key, see [ctxerror.GetFingerprint], and context and
+// tags are included as additional attributes. If the error implements slog.LogValuer,
+// its LogValue is also included. If the message is formatted, its template is
+// included under the "error.template" key and its arguments under the
+// "error.args.<name>" keys, where unnamed arguments are named by their index,
+// see [bruh.MessageTemplateOf].
 func AsAttributes(err error) []slog.Attr {
 	if err == nil {
 		return []slog.Attr{}
@@ -37,6 +42,13 @@ func AsAttributes(err error) []slog.Attr {
 	keyBuilder := &keyBuilder{}
 	keyBuilder.InitialSize(ctx)
 	attrs = append(attrs, slog.String("error", err.Error()))
+	attrs = append(attrs, slog.String("error.fingerprint", ctxerror.GetFingerprint(err)))
+	if tmpl := bruh.MessageTemplateOf(err); len(tmpl.Args) > 0 {
+		attrs = append(attrs, slog.String("error.template", tmpl.Template))
+		for i, arg := range tmpl.Args {
+			attrs = append(attrs, slog.Any(templateArgKey(i, arg), arg.Value))
+		}
+	}
 	for k, v := range ctx {
 		attrs = convertContextMapToAttributes(keyBuilder, k, v, attrs)
 	}
@@ -55,6 +67,15 @@ func AsAttributes(err error) []slog.Attr {
 	return attrs
 }
 
+// templateArgKey returns the attribute key of the argument of a message
+// template with the given index.
+func templateArgKey(i int, arg bruh.TemplateArg) string {
+	if arg.Name != "" {
+		return "error.args." + arg.Name
+	}
+	return "error.args." + strconv.Itoa(i)
+}
+
 // ContextToAttributes converts a Sentry-style context
 // (map[string]map[string]any) into a slice of slog.Attr. Nested maps are
 // flattened with dot notation (e.g. "group.key"). Unsupported types are
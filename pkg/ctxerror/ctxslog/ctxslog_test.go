@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
 	"github.com/aisbergg/go-bruh/pkg/ctxerror"
 	"github.com/aisbergg/go-bruh/pkg/ctxerror/ctxslog"
 )
@@ -141,6 +142,29 @@ func TestAsAttributes(t *testing.T) {
 			assert.Equal(val, got)
 		}
 	})
+
+	t.Run("FingerprintIsPresentUnderErrorFingerprintKey", func(t *testing.T) {
+		err := ctxerror.New("boom")
+		attrs := attrsByKey(ctxslog.AsAttributes(err))
+		assert.Equal(bruh.Fingerprint(err), attrs["error.fingerprint"].String())
+
+		err = ctxerror.New("boom").SetFingerprint("custom")
+		attrs = attrsByKey(ctxslog.AsAttributes(err))
+		assert.Equal(ctxerror.GetFingerprint(err), attrs["error.fingerprint"].String())
+		assert.True(bruh.Fingerprint(err) != attrs["error.fingerprint"].String())
+	})
+
+	t.Run("MessageTemplateArgumentsAreExported", func(t *testing.T) {
+		e := ctxerror.Errorf("user %s not found after %d attempts", bruh.Arg("user_id", "u1"), 3)
+		attrs := attrsByKey(ctxslog.AsAttributes(e))
+		assert.Equal("user %s not found after %d attempts", attrs["error.template"].String())
+		assert.Equal("u1", attrs["error.args.user_id"].String())
+		assert.Equal(int64(3), attrs["error.args.1"].Int64())
+
+		attrs = attrsByKey(ctxslog.AsAttributes(ctxerror.New("plain")))
+		_, hasTemplate := attrs["error.template"]
+		assert.False(hasTemplate, "errors without arguments must not export a template")
+	})
 }
 
 func TestContextToAttributes(t *testing.T) {
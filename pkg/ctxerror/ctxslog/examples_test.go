@@ -25,7 +25,8 @@ func ExampleAsAttributes() {
 
 	err := ctxerror.New("request failed").
 		SetContext("user", map[string]any{"id": "u1"}).
-		SetTag("env", "prod")
+		SetTag("env", "prod").
+		SetFingerprint("request failed")
 
 	// equivalent to logger.Error(...)
 	logger.LogAttrs(
@@ -36,5 +37,5 @@ func ExampleAsAttributes() {
 	)
 
 	// Output:
-	// level=ERROR msg="error occurred" error="request failed" user.id=u1 env=prod
+	// level=ERROR msg="error occurred" error="request failed" error.fingerprint=42d65186936e3e04191a4ee8b09719b0 user.id=u1 env=prod
 }
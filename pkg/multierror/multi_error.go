@@ -57,6 +57,10 @@ type Options struct {
 	UnwrapBehavior UnwrapBehavior
 	LimitPrint     int
 	Filter         FilterFunc
+	// Kind classifies the multi error as a whole, e.g. [bruh.KindInvalidArgument]
+	// for a collection of validation errors. If it is empty, the kind is
+	// determined by the unwrapped errors, see [bruh.KindOf].
+	Kind bruh.Kind
 }
 
 // Err is an error that can hold multiple errors.
@@ -67,6 +71,7 @@ type Err struct { //nolint: errname
 	unwrapBehavior UnwrapBehavior
 	limitPrint     int
 	filter         FilterFunc
+	kind           bruh.Kind
 }
 
 // New creates a new [Err] with the given message and options. You can add more
@@ -82,13 +87,30 @@ func New(msg string, options ...Options) MultiErrorer {
 		unwrapBehavior: opts.UnwrapBehavior,
 		limitPrint:     opts.LimitPrint,
 		filter:         opts.Filter,
+		kind:           opts.Kind,
 	}
 	return merr
 }
 
-// Errorf creates a new [Err] with a formatted message.
+// Errorf creates a new [Err] with a formatted message. The errors referenced by
+// %w verbs are added to the multi error and, like for [bruh.Errorf], left out
+// of its message.
+//
+// Example usage:
+//
+//	// same as multierror.New("closing failed") followed by Add(errRead, errWrite)
+//	merr := multierror.Errorf(multierror.Options{}, "closing failed: %w, %w", errRead, errWrite)
 func Errorf(options Options, format string, args ...any) MultiErrorer {
-	return New(fmt.Sprintf(format, args...), options)
+	merr := &Err{
+		Err:            *bruh.ErrorfSkip(1, format, args...),
+		unwrapBehavior: options.UnwrapBehavior,
+		limitPrint:     options.LimitPrint,
+		filter:         options.Filter,
+		kind:           options.Kind,
+	}
+	merr.msg = merr.Err.Message()
+	merr.Add(bruh.UnwrapAll(&merr.Err)...)
+	return merr
 }
 
 // -----------------------------------------------------------------------------
@@ -225,6 +247,12 @@ func (me *Err) Unwrap() error {
 	}
 }
 
+// Kind returns the kind of the [Err] as set by [Options], not considering the
+// contained errors. Use [bruh.KindOf] to get the kind of an error chain.
+func (me *Err) Kind() bruh.Kind {
+	return me.kind
+}
+
 // IsNil returns true if the [Err] is nil or if it contains no errors.
 func (me *Err) IsNil() bool {
 	return me == nil || len(me.errors) == 0
@@ -42,6 +42,17 @@ func TestConstructors(t *testing.T) {
 		assert.True(me.IsNil())
 		assert.Equal("error 42", me.(*Err).msg)
 	})
+
+	t.Run("ErrorfAddsErrorsReferencedByWrapVerbs", func(t *testing.T) {
+		errRead, errWrite := errors.New("read"), errors.New("write")
+		me := Errorf(Options{}, "closing %s failed: %w, %w", "file", errRead, errWrite)
+		assert.Equal("closing file failed", me.(*Err).msg)
+		assert.Equal([]error{errRead, errWrite}, me.Errors())
+		assert.True(errors.Is(me, errRead))
+
+		me = Errorf(Options{Filter: func(err error) bool { return err != errRead }}, "closing: %w", errRead)
+		assert.True(me.IsNil(), "expected filtered error to be left out")
+	})
 }
 
 // -----------------------------------------------------------------------------
@@ -131,6 +142,25 @@ func TestOptLimitPrint(t *testing.T) {
 	assertLimitShowsAllErrors("LimitNegativeShowsAllErrors", -1)
 }
 
+func TestOptKind(t *testing.T) {
+	t.Parallel()
+
+	assertKind := func(name string, opts Options, errs []error, expected bruh.Kind) {
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			me := New("test", opts)
+			me.Add(errs...)
+			assert.Equal(expected, bruh.KindOf(me))
+		})
+	}
+
+	notFound := bruh.NewKind(bruh.KindNotFound, "not found")
+	assertKind("OwnKind", Options{Kind: bruh.KindInvalidArgument}, []error{notFound}, bruh.KindInvalidArgument)
+	assertKind("KindOfFirstError", Options{}, []error{notFound, errors.New("other")}, bruh.KindNotFound)
+	assertKind("KindOfLastError", Options{UnwrapBehavior: UnwrapLast}, []error{notFound, errors.New("other")}, bruh.KindUnknown)
+	assertKind("NoUnwrap", Options{UnwrapBehavior: UnwrapNone}, []error{notFound}, bruh.KindUnknown)
+}
+
 func TestOptFilter(t *testing.T) {
 	t.Parallel()
 	assert := testutils.NewAssert(t)
@@ -0,0 +1,165 @@
+// Package symbolizer resolves the program counters of PC-only error reports
+// (see [bruh.NewPCReport]) into stack frames. It reads the symbol information
+// from the unstripped binary that created the reports, therefore the reports
+// can be symbolized offline, in a different process or on a different machine.
+//
+// Inlined calls are not expanded into separate frames, because the symbol
+// table of [debug/gosym] lacks the necessary information. The frame of an
+// inlined call is attributed to the function it was inlined into.
+package symbolizer
+
+import (
+	"debug/elf"
+	"debug/gosym"
+
+	"github.com/aisbergg/go-bruh/internal/buildid"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+)
+
+// ELF is a [bruh.Symbolizer] that resolves program counters using the symbol
+// information of an ELF binary built by the Go toolchain.
+type ELF struct {
+	file    *elf.File
+	table   *gosym.Table
+	buildID string
+	base    uint64
+}
+
+// OpenELF opens the ELF binary at the given path and reads its symbol table.
+// The binary must be closed with [ELF.Close] when it is no longer needed.
+func OpenELF(path string) (*ELF, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return nil, bruh.Wrapf(err, "opening binary '%s'", path)
+	}
+	e, err := newELF(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, bruh.Wrapf(err, "reading symbols of binary '%s'", path)
+	}
+	return e, nil
+}
+
+func newELF(f *elf.File) (*ELF, error) {
+	pclntabSec := f.Section(".gopclntab")
+	if pclntabSec == nil {
+		return nil, bruh.New("binary has no .gopclntab section")
+	}
+	pclntab, err := pclntabSec.Data()
+	if err != nil {
+		return nil, bruh.Wrap(err, "reading .gopclntab section")
+	}
+	textSec := f.Section(".text")
+	if textSec == nil {
+		return nil, bruh.New("binary has no .text section")
+	}
+	// .gosymtab is empty since Go 1.3, but still read it for older binaries
+	var symtab []byte
+	if symtabSec := f.Section(".gosymtab"); symtabSec != nil {
+		if symtab, err = symtabSec.Data(); err != nil {
+			return nil, bruh.Wrap(err, "reading .gosymtab section")
+		}
+	}
+	table, err := gosym.NewTable(symtab, gosym.NewLineTable(pclntab, textSec.Addr))
+	if err != nil {
+		return nil, bruh.Wrap(err, "parsing symbol table")
+	}
+
+	// the address of the first loadable segment is required to translate
+	// program counters of position-independent executables
+	var base uint64
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_LOAD {
+			base = prog.Vaddr - prog.Off
+			if prog.Align > 1 {
+				base &^= prog.Align - 1
+			}
+			break
+		}
+	}
+
+	return &ELF{
+		file:    f,
+		table:   table,
+		buildID: buildid.FromELF(f),
+		base:    base,
+	}, nil
+}
+
+// Close closes the underlying binary.
+func (e *ELF) Close() error {
+	return e.file.Close()
+}
+
+// BuildID returns the Go build ID of the binary. It is empty if the binary has
+// no Go build ID.
+func (e *ELF) BuildID() string {
+	return e.buildID
+}
+
+// Frames resolves the given program counter into a stack frame. The program
+// counter must be an address of the binary file, which is the same as the
+// address in memory, unless the binary is a position-independent executable.
+// Use [ELF.Symbolize] to symbolize whole reports, which takes care of the
+// translation.
+func (e *ELF) Frames(pc uintptr) []bruh.StackFrame {
+	return e.frames(pc, 0)
+}
+
+// frames resolves the given program counter, which is offset by slide from
+// the addresses of the binary file.
+func (e *ELF) frames(pc uintptr, slide uint64) []bruh.StackFrame {
+	filePC := uint64(pc) - slide
+	fn := e.table.PCToFunc(filePC)
+	if fn == nil {
+		return nil
+	}
+	offset := filePC - fn.Entry
+	// runtime.Callers returns the addresses of the instructions following the
+	// calls, so we have to look up the previous instruction instead. The
+	// runtime does the same in runtime.CallersFrames.
+	if filePC > fn.Entry {
+		filePC--
+	}
+	file, line, _ := e.table.PCToLine(filePC)
+	return []bruh.StackFrame{{
+		Name:            fn.Name,
+		File:            file,
+		Line:            line,
+		ProgramCounter:  pc,
+		ProgramCounter2: uintptr(filePC + slide),
+		Offset:          uintptr(offset),
+	}}
+}
+
+// Symbolize resolves the program counters of the given report, which must have
+// been created by [bruh.NewPCReport] in a process of this binary. An error is
+// returned if the build ID recorded in the report does not match the build ID
+// of the binary.
+func (e *ELF) Symbolize(report *bruh.Report) error {
+	var slide uint64
+	if bin := report.Binary; bin != nil {
+		if bin.BuildID != "" && e.buildID != "" && bin.BuildID != e.buildID {
+			return bruh.Errorf(
+				"build ID mismatch: report was created by binary '%s', but symbols are read from '%s'",
+				bin.BuildID, e.buildID,
+			)
+		}
+		if bin.LoadAddress != 0 {
+			slide = bin.LoadAddress - e.base
+		}
+	}
+	report.Symbolize(slidSymbolizer{elf: e, slide: slide})
+	return nil
+}
+
+// slidSymbolizer is a [bruh.Symbolizer] for program counters of a binary that
+// was loaded at a different address than the one stated in the binary.
+type slidSymbolizer struct {
+	elf   *ELF
+	slide uint64
+}
+
+func (s slidSymbolizer) Frames(pc uintptr) []bruh.StackFrame {
+	return s.elf.frames(pc, s.slide)
+}
@@ -0,0 +1,101 @@
+package symbolizer_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/aisbergg/go-bruh/internal/testutils"
+	"github.com/aisbergg/go-bruh/pkg/bruh"
+	"github.com/aisbergg/go-bruh/pkg/symbolizer"
+)
+
+//go:noinline
+func rootError() error {
+	return bruh.New("root error")
+}
+
+//go:noinline
+func wrappedError() error {
+	return bruh.Wrap(rootError(), "wrapped")
+}
+
+//go:noinline
+func joinedError() error {
+	return bruh.Wrap(bruh.Join(wrappedError(), errors.New("external error")), "joined")
+}
+
+func openTestBinary(t *testing.T) *symbolizer.ELF {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("test binary is not an ELF binary")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := symbolizer.OpenELF(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = e.Close() })
+	return e
+}
+
+func TestSymbolize(t *testing.T) {
+	t.Parallel()
+	e := openTestBinary(t)
+
+	formatters := map[string]bruh.Formatter{
+		"Bruh":        bruh.BruhFormatter,
+		"BruhStacked": bruh.BruhStackedFormatter,
+		"JavaStack":   bruh.JavaStackTraceFormatter,
+		"PythonTrace": bruh.PythonTracebackFormatter,
+	}
+
+	assertSymbolize := func(name string, err error) {
+		t.Run(name, func(t *testing.T) {
+			assert := testutils.NewAssert(t)
+			data, jerr := json.Marshal(bruh.NewPCReport(err))
+			assert.NoError(jerr)
+			report, jerr := bruh.UnmarshalReport(data)
+			assert.NoError(jerr)
+			assert.NoError(e.Symbolize(report))
+			for i := range report.Errors {
+				assert.Len(report.Errors[i].PCs, 0)
+			}
+			assert.True(len(report.Errors[0].Stack) > 0, "expected symbolized stack")
+			remote := report.Err()
+			for fname, f := range formatters {
+				assert.Equal(bruh.StringFormat(err, f), bruh.StringFormat(remote, f), fname)
+			}
+		})
+	}
+
+	assertSymbolize("Root", rootError())
+	assertSymbolize("Wrapped", wrappedError())
+	assertSymbolize("Joined", joinedError())
+}
+
+func TestSymbolizeBuildIDMismatch(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+	e := openTestBinary(t)
+
+	assert.True(e.BuildID() != "", "expected build ID")
+	report := bruh.NewPCReport(rootError())
+	assert.Equal(e.BuildID(), report.Binary.BuildID)
+	report.Binary.BuildID = "other"
+	assert.Error(e.Symbolize(report))
+	assert.True(len(report.Errors[0].PCs) > 0, "expected report to be untouched")
+}
+
+func TestOpenELF(t *testing.T) {
+	t.Parallel()
+	assert := testutils.NewAssert(t)
+
+	_, err := symbolizer.OpenELF("does-not-exist")
+	assert.Error(err)
+}